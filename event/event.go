@@ -12,6 +12,19 @@ const (
 	WindowHide Type = "window.hide"
 	TabSwitch  Type = "tab.switch"
 	TrayReady  Type = "tray.ready"
+	TrayExit   Type = "tray.exit"
+	ChatExport Type = "chat.export"
+
+	ToolApproval      Type = "tool.approval"
+	ScheduledCapture  Type = "capture.scheduled"
+	TextRecognized    Type = "ocr.recognized"
+	ClipboardChanged  Type = "clipboard.changed"
+	HotkeyPressed     Type = "hotkey.pressed"
+	ShortcutTriggered Type = "shortcut.triggered"
+	FocusChanged      Type = "focus.changed"
+	MenuClicked       Type = "menu.clicked"
+	TrayClicked       Type = "tray.clicked"
+	TrayMenuClicked   Type = "tray.menu.clicked"
 )
 
 // Event 事件
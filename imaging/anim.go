@@ -0,0 +1,230 @@
+package imaging
+
+import (
+	"bufio"
+	"compress/lzw"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"sort"
+	"time"
+)
+
+// Frame 动画的一帧
+type Frame struct {
+	Image image.Image
+	Delay time.Duration // 本帧显示时长
+}
+
+// ProgressFunc 编码进度回调
+type ProgressFunc func(done, total int)
+
+// GIFEncoder 逐帧写出循环播放的 GIF
+//
+// 每帧在 Add 时即量化、压缩并写出，内存中不保留已编码的帧，适合边录制边编码。
+// 所有帧以第一帧的尺寸为准，尺寸不同的帧会被裁剪或补第一种调色板颜色。
+type GIFEncoder struct {
+	buf       *bufio.Writer
+	w         errWriter
+	maxColors int
+	size      image.Point
+	frames    int
+}
+
+// NewGIFEncoder 创建 GIF 编码器，maxColors 为每帧调色板颜色数，0 表示 256
+func NewGIFEncoder(w io.Writer, maxColors int) *GIFEncoder {
+	if maxColors <= 0 || maxColors > 256 {
+		maxColors = 256
+	}
+	buf := bufio.NewWriter(w)
+	return &GIFEncoder{buf: buf, w: errWriter{w: buf}, maxColors: maxColors}
+}
+
+// Add 量化并写出一帧
+func (e *GIFEncoder) Add(f Frame) error {
+	b := f.Image.Bounds()
+	if e.frames == 0 {
+		if b.Empty() {
+			return fmt.Errorf("帧尺寸为空")
+		}
+		e.size = b.Size()
+		e.writeHeader()
+	}
+	pal := MedianCutPalette(f.Image, e.maxColors)
+	paletted := image.NewPaletted(image.Rect(0, 0, e.size.X, e.size.Y), pal)
+	draw.FloydSteinberg.Draw(paletted, paletted.Rect, f.Image, b.Min)
+	e.writeFrame(paletted, gifDelay(f.Delay))
+	e.frames++
+	return e.w.err
+}
+
+// Close 写出文件尾，不关闭底层 io.Writer
+func (e *GIFEncoder) Close() error {
+	if e.frames == 0 {
+		return fmt.Errorf("没有可编码的帧")
+	}
+	e.w.write([]byte{0x3b})
+	if e.w.err != nil {
+		return e.w.err
+	}
+	return e.buf.Flush()
+}
+
+// writeHeader 写出文件头、逻辑屏幕（不带全局调色板）与无限循环扩展
+func (e *GIFEncoder) writeHeader() {
+	w, h := e.size.X, e.size.Y
+	e.w.write([]byte("GIF89a"))
+	e.w.write([]byte{byte(w), byte(w >> 8), byte(h), byte(h >> 8), 0, 0, 0})
+	e.w.write([]byte{0x21, 0xff, 0x0b})
+	e.w.write([]byte("NETSCAPE2.0"))
+	e.w.write([]byte{0x03, 0x01, 0, 0, 0})
+}
+
+// writeFrame 写出图形控制扩展（延迟）、带局部调色板的图像描述符与 LZW 数据
+func (e *GIFEncoder) writeFrame(img *image.Paletted, delay int) {
+	e.w.write([]byte{0x21, 0xf9, 0x04, 0, byte(delay), byte(delay >> 8), 0, 0})
+
+	bits := 1
+	for 1<<bits < len(img.Palette) {
+		bits++
+	}
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	e.w.write([]byte{0x2c, 0, 0, 0, 0, byte(w), byte(w >> 8), byte(h), byte(h >> 8), 0x80 | byte(bits-1)})
+	table := make([]byte, 3<<bits)
+	for i, c := range img.Palette {
+		r, g, b, _ := c.RGBA()
+		table[3*i], table[3*i+1], table[3*i+2] = byte(r>>8), byte(g>>8), byte(b>>8)
+	}
+	e.w.write(table)
+
+	litWidth := bits
+	if litWidth < 2 {
+		litWidth = 2
+	}
+	e.w.write([]byte{byte(litWidth)})
+	blocks := &gifBlockWriter{w: &e.w}
+	lw := lzw.NewWriter(blocks, lzw.LSB, litWidth)
+	if _, err := lw.Write(img.Pix); err != nil && e.w.err == nil {
+		e.w.err = err
+	}
+	lw.Close()
+	blocks.flush()
+	e.w.write([]byte{0})
+}
+
+// gifBlockWriter 将数据拆成至多 255 字节的子块
+type gifBlockWriter struct {
+	w   *errWriter
+	buf [256]byte
+	n   int
+}
+
+func (b *gifBlockWriter) Write(p []byte) (int, error) {
+	for _, c := range p {
+		b.n++
+		b.buf[b.n] = c
+		if b.n == 255 {
+			b.flush()
+		}
+	}
+	return len(p), b.w.err
+}
+
+func (b *gifBlockWriter) flush() {
+	if b.n == 0 {
+		return
+	}
+	b.buf[0] = byte(b.n)
+	b.w.write(b.buf[:b.n+1])
+	b.n = 0
+}
+
+// gifDelay 转换为 GIF 的 1/100 秒单位；过小的延迟会被浏览器按 0.1 秒处理，因此至少 2
+func gifDelay(d time.Duration) int {
+	cs := int((d + 5*time.Millisecond) / (10 * time.Millisecond))
+	if cs < 2 {
+		cs = 2
+	}
+	return cs
+}
+
+// maxPaletteSamples 生成调色板时最多采样的像素数
+const maxPaletteSamples = 1 << 16
+
+// MedianCutPalette 用中位切分法为图片生成至多 n 色的调色板
+func MedianCutPalette(img image.Image, n int) color.Palette {
+	b := img.Bounds()
+	total := b.Dx() * b.Dy()
+	if total == 0 || n <= 0 {
+		return color.Palette{color.Black}
+	}
+
+	step := total/maxPaletteSamples + 1
+	samples := make([][3]uint8, 0, total/step+1)
+	for i := 0; i < total; i += step {
+		x, y := b.Min.X+i%b.Dx(), b.Min.Y+i/b.Dx()
+		c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+		samples = append(samples, [3]uint8{c.R, c.G, c.B})
+	}
+
+	boxes := [][][3]uint8{samples}
+	for len(boxes) < n {
+		// 选取跨度最大的盒子沿最宽的通道从中位数处切开
+		best, channel, spread := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			ch, sp := widestChannel(box)
+			if sp > spread {
+				best, channel, spread = i, ch, sp
+			}
+		}
+		if best < 0 {
+			break
+		}
+		box := boxes[best]
+		sort.Slice(box, func(i, j int) bool { return box[i][channel] < box[j][channel] })
+		mid := len(box) / 2
+		boxes[best] = box[:mid]
+		boxes = append(boxes, box[mid:])
+	}
+
+	pal := make(color.Palette, 0, len(boxes))
+	for _, box := range boxes {
+		var sum [3]int
+		for _, c := range box {
+			sum[0] += int(c[0])
+			sum[1] += int(c[1])
+			sum[2] += int(c[2])
+		}
+		n := len(box)
+		pal = append(pal, color.RGBA{uint8(sum[0] / n), uint8(sum[1] / n), uint8(sum[2] / n), 0xff})
+	}
+	return pal
+}
+
+// widestChannel 返回取值跨度最大的通道及其跨度
+func widestChannel(box [][3]uint8) (int, int) {
+	lo := [3]uint8{255, 255, 255}
+	var hi [3]uint8
+	for _, c := range box {
+		for k := 0; k < 3; k++ {
+			if c[k] < lo[k] {
+				lo[k] = c[k]
+			}
+			if c[k] > hi[k] {
+				hi[k] = c[k]
+			}
+		}
+	}
+	channel, spread := 0, -1
+	for k := 0; k < 3; k++ {
+		if s := int(hi[k]) - int(lo[k]); s > spread {
+			channel, spread = k, s
+		}
+	}
+	return channel, spread
+}
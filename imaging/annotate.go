@@ -0,0 +1,210 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+// Tool 标注工具
+type Tool int
+
+const (
+	ToolArrow     Tool = iota // 箭头
+	ToolRect                  // 矩形框
+	ToolPen                   // 自由画笔
+	ToolText                  // 文字
+	ToolHighlight             // 半透明高亮
+	ToolPixelate              // 马赛克
+	ToolBlur                  // 模糊
+)
+
+// String 返回工具名称
+func (t Tool) String() string {
+	switch t {
+	case ToolArrow:
+		return "arrow"
+	case ToolRect:
+		return "rect"
+	case ToolPen:
+		return "pen"
+	case ToolText:
+		return "text"
+	case ToolHighlight:
+		return "highlight"
+	case ToolPixelate:
+		return "pixelate"
+	case ToolBlur:
+		return "blur"
+	}
+	return "unknown"
+}
+
+// 标注默认参数
+const (
+	DefaultWidth        = 3  // 线宽
+	DefaultPixelateSize = 10 // 马赛克色块边长
+	DefaultBlurRadius   = 6  // 模糊半径
+	highlightAlpha      = 96 // 不透明的高亮颜色会被调整为此透明度
+)
+
+// Annotation 一个标注，坐标均为图片坐标
+type Annotation struct {
+	Tool   Tool
+	Points []image.Point // 箭头为起止点，矩形类为对角两点，画笔为轨迹，文字为左上角
+	Color  color.NRGBA
+	Width  int    // 线宽，0 表示默认
+	Text   string // ToolText 的文字
+	Size   int    // 马赛克色块边长或模糊半径，0 表示默认
+}
+
+// Rect 矩形类标注的区域（首尾两点张成的矩形）
+func (a Annotation) Rect() image.Rectangle {
+	if len(a.Points) == 0 {
+		return image.Rectangle{}
+	}
+	return image.Rectangle{Min: a.Points[0], Max: a.Points[len(a.Points)-1]}.Canon()
+}
+
+// Draw 将标注绘制到 dst 上，face 为空时使用内置等宽字体
+func (a Annotation) Draw(dst *image.RGBA, face font.Face) {
+	if len(a.Points) == 0 {
+		return
+	}
+	width := a.Width
+	if width <= 0 {
+		width = DefaultWidth
+	}
+
+	switch a.Tool {
+	case ToolArrow:
+		DrawArrow(dst, a.Points[0], a.Points[len(a.Points)-1], width, a.Color)
+	case ToolRect:
+		StrokeRect(dst, a.Rect(), width, a.Color)
+	case ToolPen:
+		StrokePolyline(dst, a.Points, width, a.Color)
+	case ToolText:
+		if face == nil {
+			face = basicfont.Face7x13
+		}
+		DrawText(dst, a.Points[0], a.Text, face, a.Color)
+	case ToolHighlight:
+		c := a.Color
+		if c.A == 0xff {
+			c.A = highlightAlpha
+		}
+		FillRect(dst, a.Rect(), c)
+	case ToolPixelate:
+		size := a.Size
+		if size <= 0 {
+			size = DefaultPixelateSize
+		}
+		Pixelate(dst, a.Rect(), size)
+	case ToolBlur:
+		size := a.Size
+		if size <= 0 {
+			size = DefaultBlurRadius
+		}
+		Blur(dst, a.Rect(), size)
+	}
+}
+
+// Document 标注文档：底图加按顺序叠加的标注，支持撤销/重做
+type Document struct {
+	base  image.Image
+	items []Annotation
+	undo  [][]Annotation // 每次修改前的标注列表
+	redo  [][]Annotation
+	face  font.Face
+}
+
+// NewDocument 以 base 为底图创建标注文档
+func NewDocument(base image.Image) *Document {
+	return &Document{base: base}
+}
+
+// Base 底图
+func (d *Document) Base() image.Image {
+	return d.base
+}
+
+// SetFace 设置文字标注使用的字体
+func (d *Document) SetFace(face font.Face) {
+	d.face = face
+}
+
+// Annotations 当前的标注列表
+func (d *Document) Annotations() []Annotation {
+	return append([]Annotation(nil), d.items...)
+}
+
+// Add 添加标注
+func (d *Document) Add(a Annotation) {
+	a.Points = append([]image.Point(nil), a.Points...)
+	// 三下标切片保证追加时复制，已保存的撤销快照不会被改写
+	d.push(append(d.items[:len(d.items):len(d.items)], a))
+}
+
+// Clear 清除所有标注（可撤销）
+func (d *Document) Clear() {
+	if len(d.items) > 0 {
+		d.push(nil)
+	}
+}
+
+func (d *Document) push(items []Annotation) {
+	d.undo = append(d.undo, d.items)
+	d.redo = nil
+	d.items = items
+}
+
+// CanUndo 是否可以撤销
+func (d *Document) CanUndo() bool {
+	return len(d.undo) > 0
+}
+
+// CanRedo 是否可以重做
+func (d *Document) CanRedo() bool {
+	return len(d.redo) > 0
+}
+
+// Undo 撤销上一次修改
+func (d *Document) Undo() bool {
+	if len(d.undo) == 0 {
+		return false
+	}
+	d.redo = append(d.redo, d.items)
+	d.items = d.undo[len(d.undo)-1]
+	d.undo = d.undo[:len(d.undo)-1]
+	return true
+}
+
+// Redo 重做上一次撤销的修改
+func (d *Document) Redo() bool {
+	if len(d.redo) == 0 {
+		return false
+	}
+	d.undo = append(d.undo, d.items)
+	d.items = d.redo[len(d.redo)-1]
+	d.redo = d.redo[:len(d.redo)-1]
+	return true
+}
+
+// Render 将所有标注渲染到底图副本上
+func (d *Document) Render() *image.RGBA {
+	var dst *image.RGBA
+	if d.base != nil {
+		b := d.base.Bounds()
+		dst = image.NewRGBA(b)
+		draw.Draw(dst, b, d.base, b.Min, draw.Src)
+	} else {
+		dst = image.NewRGBA(image.Rectangle{})
+	}
+	for _, a := range d.items {
+		a.Draw(dst, d.face)
+	}
+	return dst
+}
@@ -0,0 +1,176 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+var (
+	white = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	red   = color.NRGBA{R: 255, A: 255}
+)
+
+// whiteImage 纯白底图
+func whiteImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Rect, image.NewUniform(white), image.Point{}, draw.Src)
+	return img
+}
+
+func rgbaAt(img *image.RGBA, x, y int) color.NRGBA {
+	c := img.RGBAAt(x, y)
+	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}
+}
+
+func TestDocumentRenderRect(t *testing.T) {
+	base := whiteImage(40, 40)
+	doc := NewDocument(base)
+	doc.Add(Annotation{Tool: ToolRect, Points: []image.Point{{10, 10}, {30, 30}}, Color: red, Width: 1})
+
+	out := doc.Render()
+	if got := rgbaAt(out, 10, 20); got != red {
+		t.Errorf("左边框像素 = %v，期望红色", got)
+	}
+	if got := rgbaAt(out, 29, 29); got != red {
+		t.Errorf("右下角像素 = %v，期望红色", got)
+	}
+	if got := rgbaAt(out, 20, 20); got != white {
+		t.Errorf("矩形内部 = %v，期望保持白色", got)
+	}
+	if got := rgbaAt(base, 10, 20); got != white {
+		t.Error("Render 不应修改底图")
+	}
+}
+
+func TestDocumentRenderOrder(t *testing.T) {
+	blue := color.NRGBA{B: 255, A: 255}
+	doc := NewDocument(whiteImage(20, 20))
+	doc.Add(Annotation{Tool: ToolPen, Points: []image.Point{{0, 10}, {19, 10}}, Color: red, Width: 3})
+	doc.Add(Annotation{Tool: ToolPen, Points: []image.Point{{10, 0}, {10, 19}}, Color: blue, Width: 3})
+
+	// 后添加的标注画在上层
+	if got := rgbaAt(doc.Render(), 10, 10); got != blue {
+		t.Errorf("交叉点 = %v，期望蓝色", got)
+	}
+}
+
+func TestDocumentRenderHighlight(t *testing.T) {
+	doc := NewDocument(whiteImage(10, 10))
+	doc.Add(Annotation{Tool: ToolHighlight, Points: []image.Point{{0, 0}, {10, 10}}, Color: color.NRGBA{G: 255, A: 255}})
+
+	got := rgbaAt(doc.Render(), 5, 5)
+	// 不透明颜色按 highlightAlpha 与底图混合：绿色保持满值，红蓝变淡但不为 0
+	if got.G != 255 || got.R == 255 || got.R == 0 {
+		t.Errorf("高亮像素 = %v，期望半透明绿色", got)
+	}
+}
+
+func TestDocumentRenderPixelate(t *testing.T) {
+	base := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			base.Set(x, y, color.RGBA{R: uint8(x * 30), G: uint8(y * 30), A: 255})
+		}
+	}
+	doc := NewDocument(base)
+	doc.Add(Annotation{Tool: ToolPixelate, Points: []image.Point{{0, 0}, {8, 8}}, Size: 4})
+
+	out := doc.Render()
+	for _, block := range []image.Point{{0, 0}, {4, 0}, {0, 4}, {4, 4}} {
+		want := out.RGBAAt(block.X, block.Y)
+		for y := block.Y; y < block.Y+4; y++ {
+			for x := block.X; x < block.X+4; x++ {
+				if got := out.RGBAAt(x, y); got != want {
+					t.Fatalf("色块 %v 内 (%d,%d) = %v，期望与色块一致 %v", block, x, y, got, want)
+				}
+			}
+		}
+	}
+	if out.RGBAAt(0, 0) == out.RGBAAt(4, 4) {
+		t.Error("不同色块的颜色应不同")
+	}
+}
+
+func TestDocumentRenderText(t *testing.T) {
+	doc := NewDocument(whiteImage(60, 20))
+	doc.Add(Annotation{Tool: ToolText, Points: []image.Point{{2, 2}}, Text: "Hi", Color: red})
+
+	out := doc.Render()
+	found := false
+	for y := 0; y < 20 && !found; y++ {
+		for x := 0; x < 60; x++ {
+			if c := rgbaAt(out, x, y); c.R == 255 && c.G < 128 {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		t.Error("文字标注没有绘制任何像素")
+	}
+}
+
+func TestDocumentUndoRedo(t *testing.T) {
+	doc := NewDocument(whiteImage(10, 10))
+	if doc.CanUndo() || doc.CanRedo() || doc.Undo() || doc.Redo() {
+		t.Fatal("新文档不应可以撤销或重做")
+	}
+
+	a := Annotation{Tool: ToolRect, Points: []image.Point{{1, 1}, {5, 5}}, Color: red}
+	b := Annotation{Tool: ToolArrow, Points: []image.Point{{0, 0}, {9, 9}}, Color: red}
+	doc.Add(a)
+	doc.Add(b)
+	if n := len(doc.Annotations()); n != 2 {
+		t.Fatalf("标注数 = %d", n)
+	}
+
+	if !doc.Undo() || len(doc.Annotations()) != 1 || doc.Annotations()[0].Tool != ToolRect {
+		t.Fatalf("撤销一次后应只剩矩形，得到 %v", doc.Annotations())
+	}
+	if !doc.CanRedo() {
+		t.Fatal("撤销后应可以重做")
+	}
+	if !doc.Redo() || len(doc.Annotations()) != 2 {
+		t.Fatalf("重做后应恢复两个标注，得到 %v", doc.Annotations())
+	}
+
+	// 撤销后新增标注会清空重做记录，且不改写撤销快照
+	doc.Undo()
+	doc.Add(Annotation{Tool: ToolPen, Points: []image.Point{{2, 2}, {3, 3}}, Color: red})
+	if doc.CanRedo() {
+		t.Error("新增标注后不应可以重做")
+	}
+	doc.Undo()
+	if got := doc.Annotations(); len(got) != 1 || got[0].Tool != ToolRect {
+		t.Errorf("撤销新增后应回到只有矩形，得到 %v", got)
+	}
+}
+
+func TestDocumentClearIsUndoable(t *testing.T) {
+	doc := NewDocument(whiteImage(10, 10))
+	doc.Clear()
+	if doc.CanUndo() {
+		t.Error("没有标注时 Clear 不应产生撤销记录")
+	}
+
+	doc.Add(Annotation{Tool: ToolRect, Points: []image.Point{{1, 1}, {5, 5}}, Color: red})
+	doc.Clear()
+	if len(doc.Annotations()) != 0 {
+		t.Fatal("Clear 后应没有标注")
+	}
+	if !doc.Undo() || len(doc.Annotations()) != 1 {
+		t.Error("Clear 应可以撤销")
+	}
+}
+
+func TestDocumentAddCopiesPoints(t *testing.T) {
+	doc := NewDocument(whiteImage(10, 10))
+	pts := []image.Point{{1, 1}, {5, 5}}
+	doc.Add(Annotation{Tool: ToolRect, Points: pts, Color: red})
+	pts[0] = image.Pt(9, 9)
+	if got := doc.Annotations()[0].Points[0]; got != image.Pt(1, 1) {
+		t.Errorf("修改调用方的切片不应影响已添加的标注，得到 %v", got)
+	}
+}
@@ -0,0 +1,213 @@
+package imaging
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/draw"
+	"io"
+	"time"
+)
+
+// pngSignature PNG 文件头
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// EncodeAPNG 将帧编码为循环播放的 APNG（RGBA 真彩色，不支持 APNG 的查看器只显示第一帧）
+//
+// 所有帧以第一帧的尺寸为准，尺寸不同的帧会被裁剪或补透明。
+func EncodeAPNG(ctx context.Context, w io.Writer, frames []Frame, progress ProgressFunc) error {
+	if len(frames) == 0 {
+		return fmt.Errorf("没有可编码的帧")
+	}
+	enc := &APNGEncoder{enc: chunkWriter{errWriter: errWriter{w: w}}, total: len(frames)}
+	for i, f := range frames {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := enc.Add(f); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(i+1, len(frames))
+		}
+	}
+	return enc.Close()
+}
+
+// APNGEncoder 逐帧写出循环播放的 APNG
+//
+// 每帧在 Add 时即压缩并写出，内存中不保留已编码的帧。帧数在 Close 时回填到 acTL 块，
+// 因此输出必须可以 Seek。
+type APNGEncoder struct {
+	enc    chunkWriter
+	seeker io.Seeker
+	size   image.Point
+	canvas *image.NRGBA
+	frames int
+	total  int   // 预先确定的帧数，0 表示 Close 时回填
+	actl   int64 // acTL 块在输出中的偏移
+}
+
+// NewAPNGEncoder 创建 APNG 编码器
+func NewAPNGEncoder(w io.WriteSeeker) *APNGEncoder {
+	return &APNGEncoder{enc: chunkWriter{errWriter: errWriter{w: w}}, seeker: w}
+}
+
+// Add 压缩并写出一帧
+func (e *APNGEncoder) Add(f Frame) error {
+	if e.frames == 0 {
+		if err := e.writeHeader(f.Image.Bounds().Size()); err != nil {
+			return err
+		}
+	}
+
+	fctl := make([]byte, 26)
+	binary.BigEndian.PutUint32(fctl[0:], e.enc.nextSeq())
+	binary.BigEndian.PutUint32(fctl[4:], uint32(e.size.X))
+	binary.BigEndian.PutUint32(fctl[8:], uint32(e.size.Y))
+	// x/y 偏移为 0；延迟以毫秒为单位
+	binary.BigEndian.PutUint16(fctl[20:], apngDelay(f.Delay))
+	binary.BigEndian.PutUint16(fctl[22:], 1000)
+	fctl[24] = 0 // dispose: none
+	fctl[25] = 0 // blend: source
+	e.enc.chunk("fcTL", fctl)
+
+	draw.Draw(e.canvas, e.canvas.Rect, image.Transparent, image.Point{}, draw.Src)
+	draw.Draw(e.canvas, e.canvas.Rect, f.Image, f.Image.Bounds().Min, draw.Src)
+	data, err := compressScanlines(e.canvas)
+	if err != nil {
+		return err
+	}
+	if e.frames == 0 {
+		e.enc.chunk("IDAT", data)
+	} else {
+		fdat := make([]byte, 4, 4+len(data))
+		binary.BigEndian.PutUint32(fdat, e.enc.nextSeq())
+		e.enc.chunk("fdAT", append(fdat, data...))
+	}
+	e.frames++
+	return e.enc.err
+}
+
+// Close 写出文件尾并回填帧数，不关闭底层 io.Writer
+func (e *APNGEncoder) Close() error {
+	if e.frames == 0 {
+		return fmt.Errorf("没有可编码的帧")
+	}
+	e.enc.chunk("IEND", nil)
+	if e.enc.err != nil || e.total > 0 {
+		return e.enc.err
+	}
+	if _, err := e.seeker.Seek(e.actl, io.SeekStart); err != nil {
+		return err
+	}
+	e.enc.chunk("acTL", apngControl(e.frames))
+	if _, err := e.seeker.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+	return e.enc.err
+}
+
+// writeHeader 写出文件头、IHDR 与 acTL，帧数未知时 acTL 暂写 0
+func (e *APNGEncoder) writeHeader(size image.Point) error {
+	if size.X <= 0 || size.Y <= 0 {
+		return fmt.Errorf("帧尺寸为空")
+	}
+	e.size = size
+	e.canvas = image.NewNRGBA(image.Rect(0, 0, size.X, size.Y))
+	if e.total == 0 {
+		start, err := e.seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		// 签名之后是 IHDR 块（长度 4 + 类型 4 + 数据 13 + CRC 4）
+		e.actl = start + int64(len(pngSignature)) + 4 + 4 + 13 + 4
+	}
+
+	e.enc.write(pngSignature)
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], uint32(size.X))
+	binary.BigEndian.PutUint32(ihdr[4:], uint32(size.Y))
+	ihdr[8] = 8 // 位深
+	ihdr[9] = 6 // RGBA
+	e.enc.chunk("IHDR", ihdr)
+	e.enc.chunk("acTL", apngControl(e.total))
+	return e.enc.err
+}
+
+// apngControl acTL 块数据：帧数与无限循环
+func apngControl(frames int) []byte {
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:], uint32(frames))
+	binary.BigEndian.PutUint32(actl[4:], 0)
+	return actl
+}
+
+// apngDelay 帧延迟（毫秒），超出 uint16 时截断
+func apngDelay(d time.Duration) uint16 {
+	ms := d.Milliseconds()
+	if ms > 0xffff {
+		ms = 0xffff
+	}
+	if ms < 0 {
+		ms = 0
+	}
+	return uint16(ms)
+}
+
+// compressScanlines 用 Sub 过滤器处理扫描线后 zlib 压缩
+func compressScanlines(img *image.NRGBA) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	rowLen := img.Rect.Dx() * 4
+	line := make([]byte, 1+rowLen)
+	line[0] = 1 // Sub
+	for y := 0; y < img.Rect.Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+rowLen]
+		for i := 0; i < rowLen; i++ {
+			var left byte
+			if i >= 4 {
+				left = row[i-4]
+			}
+			line[1+i] = row[i] - left
+		}
+		if _, err := zw.Write(line); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// chunkWriter 按 PNG 块格式写出数据，PNG 元数据与 APNG 共用
+type chunkWriter struct {
+	errWriter
+	seq uint32
+}
+
+// nextSeq APNG fcTL/fdAT 块的序号
+func (e *chunkWriter) nextSeq() uint32 {
+	s := e.seq
+	e.seq++
+	return s
+}
+
+// chunk 写出一个块：长度、类型、数据与 CRC
+func (e *chunkWriter) chunk(name string, data []byte) {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(data)))
+	copy(header[4:], name)
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+
+	e.write(header)
+	e.write(data)
+	e.write(binary.BigEndian.AppendUint32(nil, crc.Sum32()))
+}
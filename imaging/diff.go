@@ -0,0 +1,239 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sort"
+)
+
+// ChangeRatio 两张图片之间发生变化的像素比例（0~1）
+//
+// 任一通道差值超过 tolerance 的像素视为变化；尺寸不同时视为完全变化。
+func ChangeRatio(a, b image.Image, tolerance uint8) float64 {
+	ab, bb := a.Bounds(), b.Bounds()
+	if ab.Size() != bb.Size() {
+		return 1
+	}
+	total := ab.Dx() * ab.Dy()
+	if total == 0 {
+		return 0
+	}
+
+	ra, rb := toRGBA(a), toRGBA(b)
+	changed := 0
+	for y := 0; y < ab.Dy(); y++ {
+		for x := 0; x < ab.Dx(); x++ {
+			if pixelChanged(ra, rb, ab.Min.X+x, ab.Min.Y+y, bb.Min.X+x, bb.Min.Y+y, tolerance) {
+				changed++
+			}
+		}
+	}
+	return float64(changed) / float64(total)
+}
+
+// pixelChanged 比较 a 中 (ax, ay) 与 b 中 (bx, by) 的像素，直接比较字节避免接口调用
+func pixelChanged(a, b *image.RGBA, ax, ay, bx, by int, tolerance uint8) bool {
+	i, j := a.PixOffset(ax, ay), b.PixOffset(bx, by)
+	for k := 0; k < 4; k++ {
+		if absDiff(a.Pix[i+k], b.Pix[j+k]) > tolerance {
+			return true
+		}
+	}
+	return false
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// 差异检测默认参数
+const (
+	DefaultDiffTolerance = 16 // 通道差值超过此值视为变化，可忽略压缩噪声与抗锯齿
+	DefaultDiffMerge     = 8  // 距离小于此像素数的变化合并为同一区域
+)
+
+// DiffOptions 差异检测选项，零值使用默认参数
+type DiffOptions struct {
+	Tolerance     uint8 // 通道差值容差，0 表示默认
+	Exact         bool  // 精确比较：任何通道差异都算变化，忽略 Tolerance
+	MergeDistance int   // 合并距离
+	MinArea       int   // 变化像素少于此数的区域忽略
+}
+
+// DiffResult 差异检测结果
+type DiffResult struct {
+	Bounds     image.Rectangle   // 比较范围（两图尺寸的并集，左上角为 0,0）
+	Regions    []image.Rectangle // 变化区域，按面积从大到小
+	Changed    int               // 变化的像素数
+	Similarity float64           // 相似度 0~1，1 表示完全相同
+	Mask       *image.Alpha      // 变化像素为 0xff
+}
+
+// Diff 比较两张图片，返回变化区域与相似度
+//
+// 两图以左上角对齐；尺寸不同时，只在其中一张图中存在的像素视为变化。
+func Diff(a, b image.Image, opts DiffOptions) DiffResult {
+	switch {
+	case opts.Exact:
+		opts.Tolerance = 0
+	case opts.Tolerance == 0:
+		opts.Tolerance = DefaultDiffTolerance
+	}
+	if opts.MergeDistance <= 0 {
+		opts.MergeDistance = DefaultDiffMerge
+	}
+	ra, rb := toRGBA(a), toRGBA(b)
+	sa, sb := ra.Rect.Size(), rb.Rect.Size()
+	bounds := image.Rect(0, 0, max(sa.X, sb.X), max(sa.Y, sb.Y))
+	common := image.Rect(0, 0, min(sa.X, sb.X), min(sa.Y, sb.Y))
+
+	res := DiffResult{Bounds: bounds, Mask: image.NewAlpha(bounds)}
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			changed := true
+			if image.Pt(x, y).In(common) {
+				changed = pixelChanged(ra, rb, ra.Rect.Min.X+x, ra.Rect.Min.Y+y, rb.Rect.Min.X+x, rb.Rect.Min.Y+y, opts.Tolerance)
+			}
+			if changed {
+				res.Mask.Pix[y*res.Mask.Stride+x] = 0xff
+				res.Changed++
+			}
+		}
+	}
+	if total := bounds.Dx() * bounds.Dy(); total > 0 {
+		res.Similarity = 1 - float64(res.Changed)/float64(total)
+	} else {
+		res.Similarity = 1
+	}
+	res.Regions = diffRegions(res.Mask, opts.MergeDistance, opts.MinArea)
+	return res
+}
+
+// diffCell 网格单元内变化像素的范围
+type diffCell struct {
+	bounds image.Rectangle
+	count  int
+	label  int
+}
+
+// diffRegions 将变化像素按 cell×cell 网格分块，相邻的非空网格连通为一个区域
+func diffRegions(mask *image.Alpha, cell, minArea int) []image.Rectangle {
+	b := mask.Rect
+	cols, rows := (b.Dx()+cell-1)/cell, (b.Dy()+cell-1)/cell
+	cells := make([]diffCell, cols*rows)
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			if mask.Pix[y*mask.Stride+x] == 0 {
+				continue
+			}
+			c := &cells[(y/cell)*cols+x/cell]
+			c.bounds = c.bounds.Union(image.Rect(x, y, x+1, y+1))
+			c.count++
+		}
+	}
+
+	type region struct {
+		rect  image.Rectangle
+		count int
+	}
+	var regions []region
+	var stack []int
+	for start := range cells {
+		if cells[start].count == 0 || cells[start].label != 0 {
+			continue
+		}
+		label := len(regions) + 1
+		r := region{}
+		cells[start].label = label
+		stack = append(stack[:0], start)
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			r.rect = r.rect.Union(cells[i].bounds)
+			r.count += cells[i].count
+			cx, cy := i%cols, i/cols
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					nx, ny := cx+dx, cy+dy
+					if nx < 0 || ny < 0 || nx >= cols || ny >= rows {
+						continue
+					}
+					j := ny*cols + nx
+					if cells[j].count > 0 && cells[j].label == 0 {
+						cells[j].label = label
+						stack = append(stack, j)
+					}
+				}
+			}
+		}
+		regions = append(regions, r)
+	}
+
+	var out []image.Rectangle
+	for _, r := range regions {
+		if r.count >= minArea {
+			out = append(out, r.rect)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Dx()*out[i].Dy() > out[j].Dx()*out[j].Dy()
+	})
+	return out
+}
+
+// HighlightDiff 以 base 为底图，淡化未变化的像素并用 c 标出变化像素与区域边框
+func HighlightDiff(base image.Image, d DiffResult, c color.NRGBA) *image.RGBA {
+	dst := image.NewRGBA(d.Bounds)
+	src := toRGBA(base)
+	draw.Draw(dst, dst.Rect, image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Rect, src, src.Rect.Min, draw.Over)
+	// 未变化的部分叠一层半透明白色，变化像素着色
+	fade := color.NRGBA{255, 255, 255, 160}
+	for y := 0; y < d.Bounds.Dy(); y++ {
+		for x := 0; x < d.Bounds.Dx(); x++ {
+			if d.Mask.Pix[y*d.Mask.Stride+x] != 0 {
+				blend(dst, x, y, color.NRGBA{c.R, c.G, c.B, 200})
+			} else {
+				blend(dst, x, y, fade)
+			}
+		}
+	}
+	for _, r := range d.Regions {
+		StrokeRect(dst, r.Inset(-2), 2, c)
+	}
+	return dst
+}
+
+// Mix 按 t（0~1）混合两张图片：0 为 a，1 为 b，两图以左上角对齐
+func Mix(a, b image.Image, t float64) *image.RGBA {
+	ra, rb := toRGBA(a), toRGBA(b)
+	sa, sb := ra.Rect.Size(), rb.Rect.Size()
+	dst := image.NewRGBA(image.Rect(0, 0, max(sa.X, sb.X), max(sa.Y, sb.Y)))
+	draw.Draw(dst, ra.Rect.Sub(ra.Rect.Min), ra, ra.Rect.Min, draw.Src)
+	alpha := uint8(math.Round(math.Max(0, math.Min(1, t)) * 255))
+	mask := image.NewUniform(color.Alpha{A: alpha})
+	draw.DrawMask(dst, rb.Rect.Sub(rb.Rect.Min), rb, rb.Rect.Min, mask, image.Point{}, draw.Over)
+	return dst
+}
+
+// toRGBA 取得 *image.RGBA；嵌入了 *image.RGBA 的类型（如截图）通过 SubImage 共享像素
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	if s, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		if rgba, ok := s.SubImage(img.Bounds()).(*image.RGBA); ok {
+			return rgba
+		}
+	}
+	rgba := image.NewRGBA(img.Bounds())
+	draw.Draw(rgba, rgba.Rect, img, rgba.Rect.Min, draw.Src)
+	return rgba
+}
@@ -0,0 +1,165 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"testing"
+)
+
+// fillRect 将 img 中 r 范围填充为 c
+func fillRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+func TestDiffMergesNearbyChanges(t *testing.T) {
+	a := whiteImage(64, 64)
+	b := whiteImage(64, 64)
+	fillRect(b, image.Rect(2, 2, 6, 6), red)
+	fillRect(b, image.Rect(10, 2, 12, 4), red)   // 与第一块相距不到合并距离
+	fillRect(b, image.Rect(40, 40, 44, 44), red) // 远处的独立变化
+
+	d := Diff(a, b, DiffOptions{})
+	want := []image.Rectangle{image.Rect(2, 2, 12, 6), image.Rect(40, 40, 44, 44)}
+	if len(d.Regions) != len(want) {
+		t.Fatalf("Regions = %v，期望 %v", d.Regions, want)
+	}
+	for i := range want {
+		if d.Regions[i] != want[i] {
+			t.Errorf("Regions[%d] = %v，期望 %v（按面积从大到小）", i, d.Regions[i], want[i])
+		}
+	}
+	if d.Changed != 16+4+16 {
+		t.Errorf("Changed = %d，期望 36", d.Changed)
+	}
+	if got := d.Mask.AlphaAt(3, 3).A; got != 0xff {
+		t.Errorf("变化像素的 Mask = %#x，期望 0xff", got)
+	}
+	if got := d.Mask.AlphaAt(20, 20).A; got != 0 {
+		t.Errorf("未变化像素的 Mask = %#x，期望 0", got)
+	}
+}
+
+func TestDiffMinArea(t *testing.T) {
+	a := whiteImage(32, 32)
+	b := whiteImage(32, 32)
+	fillRect(b, image.Rect(0, 0, 4, 4), red)
+	b.Set(30, 30, red) // 单个噪点
+
+	d := Diff(a, b, DiffOptions{MinArea: 2})
+	if len(d.Regions) != 1 || d.Regions[0] != image.Rect(0, 0, 4, 4) {
+		t.Errorf("Regions = %v，小于 MinArea 的区域应被忽略", d.Regions)
+	}
+	if d.Changed != 17 {
+		t.Errorf("Changed = %d，忽略的区域仍应计入变化像素数", d.Changed)
+	}
+}
+
+func TestDiffTolerance(t *testing.T) {
+	a := whiteImage(4, 4)
+	b := whiteImage(4, 4)
+	b.Set(1, 1, color.RGBA{R: 250, G: 250, B: 250, A: 255}) // 通道差值 5
+
+	tests := []struct {
+		name string
+		opts DiffOptions
+		want int
+	}{
+		{"容差 0 使用默认容差", DiffOptions{}, 0},
+		{"默认容差", DiffOptions{Tolerance: DefaultDiffTolerance}, 0},
+		{"小于差值的容差", DiffOptions{Tolerance: 4}, 1},
+		{"等于差值的容差", DiffOptions{Tolerance: 5}, 0},
+		{"精确比较忽略容差", DiffOptions{Tolerance: 200, Exact: true}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(a, b, tt.opts).Changed; got != tt.want {
+				t.Errorf("Changed = %d，期望 %d", got, tt.want)
+			}
+		})
+	}
+
+	d := Diff(a, a, DiffOptions{Exact: true})
+	if d.Changed != 0 || d.Similarity != 1 || len(d.Regions) != 0 {
+		t.Errorf("相同图片 Changed=%d Similarity=%v Regions=%v，期望无变化", d.Changed, d.Similarity, d.Regions)
+	}
+}
+
+func TestDiffDifferentSizes(t *testing.T) {
+	a := whiteImage(10, 10)
+	b := whiteImage(12, 8)
+
+	d := Diff(a, b, DiffOptions{})
+	if want := image.Rect(0, 0, 12, 10); d.Bounds != want {
+		t.Fatalf("Bounds = %v，期望两图尺寸的并集 %v", d.Bounds, want)
+	}
+	// 只在一张图中存在的像素：右侧 2×8 与底部 12×2
+	if want := 12*10 - 10*8; d.Changed != want {
+		t.Errorf("Changed = %d，期望 %d", d.Changed, want)
+	}
+	if want := 1 - 40.0/120; math.Abs(d.Similarity-want) > 1e-9 {
+		t.Errorf("Similarity = %v，期望 %v", d.Similarity, want)
+	}
+	if got := d.Mask.AlphaAt(5, 5).A; got != 0 {
+		t.Errorf("重叠区域内相同像素的 Mask = %#x，期望 0", got)
+	}
+
+	// 非零原点的图片按左上角对齐
+	sub := whiteImage(20, 20).SubImage(image.Rect(5, 5, 15, 15))
+	if d := Diff(a, sub, DiffOptions{}); d.Changed != 0 {
+		t.Errorf("子图与同尺寸的图片 Changed = %d，期望 0", d.Changed)
+	}
+}
+
+func TestDiffEmptyImages(t *testing.T) {
+	empty := image.NewRGBA(image.Rect(0, 0, 0, 0))
+
+	d := Diff(empty, empty, DiffOptions{})
+	if d.Changed != 0 || d.Similarity != 1 || len(d.Regions) != 0 {
+		t.Errorf("两张空图 Changed=%d Similarity=%v Regions=%v，期望完全相同", d.Changed, d.Similarity, d.Regions)
+	}
+
+	d = Diff(empty, whiteImage(3, 2), DiffOptions{})
+	if d.Changed != 6 || d.Similarity != 0 {
+		t.Errorf("空图与 3×2 图片 Changed=%d Similarity=%v，期望全部变化", d.Changed, d.Similarity)
+	}
+	if len(d.Regions) != 1 || d.Regions[0] != image.Rect(0, 0, 3, 2) {
+		t.Errorf("Regions = %v，期望整张图片", d.Regions)
+	}
+
+	if got := ChangeRatio(empty, empty, 0); got != 0 {
+		t.Errorf("ChangeRatio(空图, 空图) = %v，期望 0", got)
+	}
+}
+
+// embeddedRGBA 模拟嵌入 *image.RGBA 的截图类型
+type embeddedRGBA struct {
+	*image.RGBA
+}
+
+func TestChangeRatio(t *testing.T) {
+	a := whiteImage(10, 10)
+	b := whiteImage(10, 10)
+	fillRect(b, image.Rect(0, 0, 5, 2), red)
+
+	// 截图类型与其他图片格式的结果与 *image.RGBA 一致
+	nrgba := image.NewNRGBA(b.Rect)
+	draw.Draw(nrgba, nrgba.Rect, b, image.Point{}, draw.Src)
+	for _, tt := range []struct {
+		name string
+		img  image.Image
+	}{
+		{"RGBA", b},
+		{"嵌入 RGBA", embeddedRGBA{b}},
+		{"NRGBA", nrgba},
+	} {
+		if got := ChangeRatio(embeddedRGBA{a}, tt.img, 8); got != 0.1 {
+			t.Errorf("%s: ChangeRatio = %v，期望 0.1", tt.name, got)
+		}
+	}
+
+	if got := ChangeRatio(a, whiteImage(10, 11), 8); got != 1 {
+		t.Errorf("尺寸不同时 ChangeRatio = %v，期望 1", got)
+	}
+}
@@ -0,0 +1,295 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// 以下绘制函数都直接修改 dst，超出 dst 的部分会被忽略
+
+// FillRect 用颜色填充矩形，颜色带透明度时与原图混合
+func FillRect(dst *image.RGBA, r image.Rectangle, c color.NRGBA) {
+	r = r.Intersect(dst.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			blend(dst, x, y, c)
+		}
+	}
+}
+
+// StrokePolyline 以指定线宽绘制折线
+func StrokePolyline(dst *image.RGBA, pts []image.Point, width int, c color.NRGBA) {
+	if len(pts) == 0 {
+		return
+	}
+	radius := width / 2
+	m := newMask(pointsBounds(pts).Inset(-radius - 1).Intersect(dst.Rect))
+	if len(pts) == 1 {
+		stampDisc(m, pts[0], radius)
+	}
+	for i := 1; i < len(pts); i++ {
+		maskLine(m, pts[i-1], pts[i], radius)
+	}
+	fillMask(dst, m, c)
+}
+
+// StrokeRect 以指定线宽绘制矩形边框
+func StrokeRect(dst *image.RGBA, r image.Rectangle, width int, c color.NRGBA) {
+	r = r.Canon()
+	br := r.Max.Sub(image.Pt(1, 1)) // 右下角像素
+	StrokePolyline(dst, []image.Point{
+		r.Min, {br.X, r.Min.Y}, br, {r.Min.X, br.Y}, r.Min,
+	}, width, c)
+}
+
+// DrawArrow 绘制从 from 指向 to 的箭头
+func DrawArrow(dst *image.RGBA, from, to image.Point, width int, c color.NRGBA) {
+	dx, dy := float64(to.X-from.X), float64(to.Y-from.Y)
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return
+	}
+
+	// 箭头头部长度随线宽变化，但不超过箭头总长
+	head := math.Max(10, float64(width)*4)
+	if head > length {
+		head = length
+	}
+	ux, uy := dx/length, dy/length
+	bx, by := float64(to.X)-ux*head, float64(to.Y)-uy*head
+	half := head * 0.6
+	left := image.Pt(round(bx-uy*half), round(by+ux*half))
+	right := image.Pt(round(bx+uy*half), round(by-ux*half))
+	base := image.Pt(round(bx), round(by))
+
+	radius := width / 2
+	m := newMask(pointsBounds([]image.Point{from, to, left, right}).Inset(-radius - 1).Intersect(dst.Rect))
+	maskLine(m, from, base, radius)
+	maskTriangle(m, to, left, right)
+	fillMask(dst, m, c)
+}
+
+// DrawText 在 pt（文字左上角）处绘制文字
+func DrawText(dst *image.RGBA, pt image.Point, text string, face font.Face, c color.NRGBA) {
+	d := font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(pt.X, pt.Y+face.Metrics().Ascent.Ceil()),
+	}
+	d.DrawString(text)
+}
+
+// TextSize 文字绘制后的尺寸
+func TextSize(text string, face font.Face) image.Point {
+	m := face.Metrics()
+	return image.Pt(font.MeasureString(face, text).Ceil(), (m.Ascent + m.Descent).Ceil())
+}
+
+// Pixelate 将矩形区域马赛克化，block 为色块边长
+func Pixelate(dst *image.RGBA, r image.Rectangle, block int) {
+	if block < 2 {
+		block = 2
+	}
+	r = r.Canon().Intersect(dst.Rect)
+	for by := r.Min.Y; by < r.Max.Y; by += block {
+		for bx := r.Min.X; bx < r.Max.X; bx += block {
+			cell := image.Rect(bx, by, bx+block, by+block).Intersect(r)
+			var sum [4]int
+			for y := cell.Min.Y; y < cell.Max.Y; y++ {
+				for x := cell.Min.X; x < cell.Max.X; x++ {
+					i := dst.PixOffset(x, y)
+					for k := 0; k < 4; k++ {
+						sum[k] += int(dst.Pix[i+k])
+					}
+				}
+			}
+			n := cell.Dx() * cell.Dy()
+			avg := color.RGBA{uint8(sum[0] / n), uint8(sum[1] / n), uint8(sum[2] / n), uint8(sum[3] / n)}
+			draw.Draw(dst, cell, image.NewUniform(avg), image.Point{}, draw.Src)
+		}
+	}
+}
+
+// Blur 对矩形区域做近似高斯模糊（三次盒式模糊），radius 为模糊半径
+func Blur(dst *image.RGBA, r image.Rectangle, radius int) {
+	r = r.Canon().Intersect(dst.Rect)
+	if radius < 1 || r.Empty() {
+		return
+	}
+	w, h := r.Dx(), r.Dy()
+	buf := make([]float64, w*h*4)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := dst.PixOffset(r.Min.X+x, r.Min.Y+y)
+			for k := 0; k < 4; k++ {
+				buf[(y*w+x)*4+k] = float64(dst.Pix[i+k])
+			}
+		}
+	}
+
+	tmp := make([]float64, len(buf))
+	for pass := 0; pass < 3; pass++ {
+		boxBlur(buf, tmp, w, h, radius, 1, w) // 水平
+		boxBlur(tmp, buf, h, w, radius, w, 1) // 垂直
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := dst.PixOffset(r.Min.X+x, r.Min.Y+y)
+			for k := 0; k < 4; k++ {
+				dst.Pix[i+k] = uint8(math.Round(buf[(y*w+x)*4+k]))
+			}
+		}
+	}
+}
+
+// boxBlur 沿一个方向做盒式模糊。n 为每行像素数，lines 为行数，
+// step 为行内相邻像素的下标间距，stride 为相邻行的下标间距（均以像素计）
+func boxBlur(src, dst []float64, n, lines, radius, step, stride int) {
+	for line := 0; line < lines; line++ {
+		start := line * stride
+		for i := 0; i < n; i++ {
+			lo, hi := i-radius, i+radius
+			if lo < 0 {
+				lo = 0
+			}
+			if hi > n-1 {
+				hi = n - 1
+			}
+			var sum [4]float64
+			for j := lo; j <= hi; j++ {
+				p := (start + j*step) * 4
+				for k := 0; k < 4; k++ {
+					sum[k] += src[p+k]
+				}
+			}
+			cnt := float64(hi - lo + 1)
+			p := (start + i*step) * 4
+			for k := 0; k < 4; k++ {
+				dst[p+k] = sum[k] / cnt
+			}
+		}
+	}
+}
+
+// --- 内部光栅化 ---
+
+// blend 按非预乘颜色的透明度混合一个像素
+func blend(dst *image.RGBA, x, y int, c color.NRGBA) {
+	i := dst.PixOffset(x, y)
+	a := uint32(c.A)
+	if a == 0xff {
+		dst.Pix[i+0], dst.Pix[i+1], dst.Pix[i+2], dst.Pix[i+3] = c.R, c.G, c.B, 0xff
+		return
+	}
+	src := [3]uint8{c.R, c.G, c.B}
+	for k := 0; k < 3; k++ {
+		dst.Pix[i+k] = uint8((uint32(src[k])*a + uint32(dst.Pix[i+k])*(0xff-a)) / 0xff)
+	}
+	dst.Pix[i+3] = uint8(a + uint32(dst.Pix[i+3])*(0xff-a)/0xff)
+}
+
+// newMask 创建覆盖范围为 r 的遮罩
+func newMask(r image.Rectangle) *image.Alpha {
+	return image.NewAlpha(r)
+}
+
+// fillMask 按遮罩覆盖的像素填充颜色，每个像素只混合一次
+func fillMask(dst *image.RGBA, m *image.Alpha, c color.NRGBA) {
+	r := m.Rect.Intersect(dst.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if m.Pix[m.PixOffset(x, y)] != 0 {
+				blend(dst, x, y, c)
+			}
+		}
+	}
+}
+
+// stampDisc 在遮罩上盖一个实心圆
+func stampDisc(m *image.Alpha, p image.Point, radius int) {
+	r2 := radius*radius + radius // 略大于 r²，使小半径的圆更饱满
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			q := image.Pt(p.X+dx, p.Y+dy)
+			if dx*dx+dy*dy <= r2 && q.In(m.Rect) {
+				m.Pix[m.PixOffset(q.X, q.Y)] = 0xff
+			}
+		}
+	}
+}
+
+// maskLine 用 Bresenham 算法沿线段盖圆
+func maskLine(m *image.Alpha, p0, p1 image.Point, radius int) {
+	dx, dy := abs(p1.X-p0.X), -abs(p1.Y-p0.Y)
+	sx, sy := 1, 1
+	if p0.X > p1.X {
+		sx = -1
+	}
+	if p0.Y > p1.Y {
+		sy = -1
+	}
+	err := dx + dy
+	p := p0
+	for {
+		stampDisc(m, p, radius)
+		if p == p1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			p.X += sx
+		}
+		if e2 <= dx {
+			err += dx
+			p.Y += sy
+		}
+	}
+}
+
+// maskTriangle 填充三角形
+func maskTriangle(m *image.Alpha, a, b, c image.Point) {
+	r := pointsBounds([]image.Point{a, b, c}).Intersect(m.Rect)
+	edge := func(p, q, s image.Point) int {
+		return (q.X-p.X)*(s.Y-p.Y) - (q.Y-p.Y)*(s.X-p.X)
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			p := image.Pt(x, y)
+			e0, e1, e2 := edge(a, b, p), edge(b, c, p), edge(c, a, p)
+			if (e0 >= 0 && e1 >= 0 && e2 >= 0) || (e0 <= 0 && e1 <= 0 && e2 <= 0) {
+				m.Pix[m.PixOffset(x, y)] = 0xff
+			}
+		}
+	}
+}
+
+// pointsBounds 点集的包围盒（包含每个点所在的像素）
+func pointsBounds(pts []image.Point) image.Rectangle {
+	if len(pts) == 0 {
+		return image.Rectangle{}
+	}
+	r := image.Rectangle{Min: pts[0], Max: pts[0].Add(image.Pt(1, 1))}
+	for _, p := range pts[1:] {
+		r = r.Union(image.Rectangle{Min: p, Max: p.Add(image.Pt(1, 1))})
+	}
+	return r
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func round(v float64) int {
+	return int(math.Round(v))
+}
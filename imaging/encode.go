@@ -0,0 +1,186 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/image/bmp"
+)
+
+// Format 图片文件格式
+type Format string
+
+const (
+	FormatPNG  Format = "png"
+	FormatJPEG Format = "jpeg"
+	FormatBMP  Format = "bmp"
+	FormatWebP Format = "webp" // 无损 WebP
+)
+
+// DefaultJPEGQuality 默认 JPEG 质量
+const DefaultJPEGQuality = 90
+
+// Ext 格式对应的文件扩展名
+func (f Format) Ext() string {
+	switch f {
+	case FormatJPEG:
+		return ".jpg"
+	case FormatBMP:
+		return ".bmp"
+	case FormatWebP:
+		return ".webp"
+	}
+	return ".png"
+}
+
+// FormatFromPath 按扩展名推断格式，无法识别时返回 PNG 与 false
+func FormatFromPath(path string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return FormatPNG, true
+	case ".jpg", ".jpeg":
+		return FormatJPEG, true
+	case ".bmp":
+		return FormatBMP, true
+	case ".webp":
+		return FormatWebP, true
+	}
+	return FormatPNG, false
+}
+
+// Metadata 写入图片文件的文本元数据
+//
+// PNG 写入 iTXt 块，JPEG 写入 COM 段；BMP 与 WebP 不支持，会被忽略。
+type Metadata map[string]string
+
+// sortedKeys 按键排序，保证输出稳定
+func (m Metadata) sortedKeys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EncodeOptions 编码选项
+type EncodeOptions struct {
+	Format   Format
+	Quality  int // JPEG 质量 1-100，0 表示默认
+	Metadata Metadata
+}
+
+// Encode 按选项编码图片
+func Encode(w io.Writer, img image.Image, opts EncodeOptions) error {
+	switch opts.Format {
+	case FormatPNG, "":
+		return encodePNG(w, img, opts.Metadata)
+	case FormatJPEG:
+		return encodeJPEG(w, img, opts.Quality, opts.Metadata)
+	case FormatBMP:
+		return bmp.Encode(w, img)
+	case FormatWebP:
+		return EncodeWebPLossless(w, img)
+	}
+	return fmt.Errorf("不支持的图片格式: %s", opts.Format)
+}
+
+// encodePNG 编码 PNG，并在 IHDR 之后插入 iTXt 元数据块
+func encodePNG(w io.Writer, img image.Image, meta Metadata) error {
+	if len(meta) == 0 {
+		return png.Encode(w, img)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+	// 签名 8 字节 + IHDR 块（长度 4 + 类型 4 + 数据 13 + CRC 4）
+	headerLen := len(pngSignature) + 4 + 4 + 13 + 4
+
+	pw := &chunkWriter{errWriter: errWriter{w: w}}
+	pw.write(data[:headerLen])
+	for _, k := range meta.sortedKeys() {
+		// 关键字\0 不压缩\0 压缩方法\0 语言\0 翻译关键字\0 UTF-8 文本
+		var chunk bytes.Buffer
+		chunk.WriteString(pngKeyword(k))
+		chunk.Write([]byte{0, 0, 0, 0, 0})
+		chunk.WriteString(meta[k])
+		pw.chunk("iTXt", chunk.Bytes())
+	}
+	pw.write(data[headerLen:])
+	return pw.err
+}
+
+// pngKeyword PNG 关键字只允许 1-79 个 Latin-1 可打印字符
+func pngKeyword(k string) string {
+	var b strings.Builder
+	for _, r := range k {
+		if r >= 0x20 && r < 0x7f {
+			b.WriteRune(r)
+		}
+	}
+	s := strings.TrimSpace(b.String())
+	if s == "" {
+		s = "Comment"
+	}
+	if len(s) > 79 {
+		s = s[:79]
+	}
+	return s
+}
+
+// encodeJPEG 编码 JPEG，并在 SOI 之后插入 COM 注释段
+func encodeJPEG(w io.Writer, img image.Image, quality int, meta Metadata) error {
+	if quality <= 0 || quality > 100 {
+		quality = DefaultJPEGQuality
+	}
+	if len(meta) == 0 {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return err
+	}
+
+	var comment strings.Builder
+	for _, k := range meta.sortedKeys() {
+		fmt.Fprintf(&comment, "%s=%s\n", k, meta[k])
+	}
+	text := comment.String()
+	const maxSegment = 0xffff - 2
+	if len(text) > maxSegment {
+		text = text[:maxSegment]
+	}
+
+	data := buf.Bytes()
+	segment := []byte{0xff, 0xfe}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(text)+2))
+	segment = append(segment, text...)
+
+	jw := &errWriter{w: w}
+	jw.write(data[:2]) // SOI
+	jw.write(segment)
+	jw.write(data[2:])
+	return jw.err
+}
+
+// errWriter 依次写出数据，记录第一个错误，之后的写入被忽略
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) write(b []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(b)
+	}
+}
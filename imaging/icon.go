@@ -0,0 +1,203 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/font/basicfont"
+)
+
+// DecodeIcon 解码图标：ICO 取其中最大的图像（支持 PNG 与 24/32 位位图），其他格式按 image.Decode 解码
+func DecodeIcon(data []byte) (image.Image, error) {
+	if len(data) >= 6 && binary.LittleEndian.Uint16(data[0:]) == 0 && binary.LittleEndian.Uint16(data[2:]) == 1 {
+		return decodeICO(data)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("解码图标失败: %w", err)
+	}
+	return img, nil
+}
+
+func decodeICO(data []byte) (image.Image, error) {
+	count := int(binary.LittleEndian.Uint16(data[4:]))
+	if len(data) < 6+count*16 {
+		return nil, errors.New("图标文件不完整")
+	}
+	best, bestSize := -1, 0
+	for i := 0; i < count; i++ {
+		entry := data[6+i*16:]
+		size := int(entry[0])
+		if size == 0 {
+			size = 256
+		}
+		if size > bestSize {
+			best, bestSize = i, size
+		}
+	}
+	if best < 0 {
+		return nil, errors.New("图标文件中没有图像")
+	}
+	entry := data[6+best*16:]
+	length := int(binary.LittleEndian.Uint32(entry[8:]))
+	offset := int(binary.LittleEndian.Uint32(entry[12:]))
+	if offset < 0 || length < 0 || offset+length > len(data) {
+		return nil, errors.New("图标文件不完整")
+	}
+	payload := data[offset : offset+length]
+	if bytes.HasPrefix(payload, pngSignature) { // Vista 起 ICO 中的图像可以直接是 PNG
+		return png.Decode(bytes.NewReader(payload))
+	}
+	return decodeIconBitmap(payload)
+}
+
+// decodeIconBitmap 解码 ICO 中的位图：BITMAPINFOHEADER 的高度为图像与 AND 遮罩之和，行自下而上
+func decodeIconBitmap(b []byte) (image.Image, error) {
+	if len(b) < 40 {
+		return nil, errors.New("图标位图不完整")
+	}
+	headerSize := int(binary.LittleEndian.Uint32(b[0:]))
+	w := int(int32(binary.LittleEndian.Uint32(b[4:])))
+	h := int(int32(binary.LittleEndian.Uint32(b[8:]))) / 2
+	bpp := int(binary.LittleEndian.Uint16(b[14:]))
+	if bpp != 32 && bpp != 24 {
+		return nil, fmt.Errorf("不支持 %d 位的图标位图", bpp)
+	}
+	if w <= 0 || h <= 0 || headerSize < 40 {
+		return nil, errors.New("图标位图尺寸无效")
+	}
+	stride := (w*bpp/8 + 3) &^ 3
+	maskStride := ((w+7)/8 + 3) &^ 3
+	pixels := b[headerSize:]
+	if len(pixels) < stride*h {
+		return nil, errors.New("图标位图不完整")
+	}
+	mask := pixels[stride*h:]
+	hasMask := len(mask) >= maskStride*h
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	anyAlpha := false
+	for y := 0; y < h; y++ {
+		row := pixels[(h-1-y)*stride:]
+		for x := 0; x < w; x++ {
+			p := row[x*bpp/8:]
+			c := color.NRGBA{R: p[2], G: p[1], B: p[0], A: 0xff}
+			if bpp == 32 {
+				c.A = p[3]
+				anyAlpha = anyAlpha || c.A != 0
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	// 24 位或 Alpha 全为 0 的旧式 32 位图标按 AND 遮罩确定透明
+	if bpp == 24 || !anyAlpha {
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				transparent := hasMask && mask[(h-1-y)*maskStride+x/8]&(0x80>>(x%8)) != 0
+				i := img.PixOffset(x, y)
+				if transparent {
+					img.Pix[i+3] = 0
+				} else {
+					img.Pix[i+3] = 0xff
+				}
+			}
+		}
+	}
+	return img, nil
+}
+
+// EncodeICO 编码为 ICO，每张图像以 PNG 格式保存，边长不能超过 256
+func EncodeICO(w io.Writer, imgs ...image.Image) error {
+	if len(imgs) == 0 {
+		return errors.New("没有要编码的图标图像")
+	}
+	payloads := make([][]byte, len(imgs))
+	for i, img := range imgs {
+		size := img.Bounds().Size()
+		if size.X > 256 || size.Y > 256 {
+			return fmt.Errorf("图标尺寸 %dx%d 超过 256", size.X, size.Y)
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return err
+		}
+		payloads[i] = buf.Bytes()
+	}
+
+	header := make([]byte, 6+16*len(imgs))
+	binary.LittleEndian.PutUint16(header[2:], 1) // 类型：图标
+	binary.LittleEndian.PutUint16(header[4:], uint16(len(imgs)))
+	offset := len(header)
+	for i, img := range imgs {
+		entry := header[6+i*16:]
+		size := img.Bounds().Size()
+		entry[0], entry[1] = uint8(size.X), uint8(size.Y) // 256 写作 0
+		binary.LittleEndian.PutUint16(entry[4:], 1)       // 色彩平面
+		binary.LittleEndian.PutUint16(entry[6:], 32)      // 位深
+		binary.LittleEndian.PutUint32(entry[8:], uint32(len(payloads[i])))
+		binary.LittleEndian.PutUint32(entry[12:], uint32(offset))
+		offset += len(payloads[i])
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	for _, p := range payloads {
+		if _, err := w.Write(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FillCircle 填充以 center 为圆心的圆
+func FillCircle(dst *image.RGBA, center image.Point, radius int, c color.NRGBA) {
+	m := newMask(image.Rect(center.X-radius, center.Y-radius, center.X+radius+1, center.Y+radius+1).Intersect(dst.Rect))
+	stampDisc(m, center, radius)
+	fillMask(dst, m, c)
+}
+
+// BadgeText 角标显示的文字，超过 99 显示为 "99+"，不大于 0 时为空
+func BadgeText(count int) string {
+	switch {
+	case count <= 0:
+		return ""
+	case count > 99:
+		return "99+"
+	}
+	return fmt.Sprint(count)
+}
+
+// DrawBadge 在图像右上角绘制圆角角标，文字较长时角标向左加宽
+func DrawBadge(dst *image.RGBA, text string, bg, fg color.NRGBA) {
+	if text == "" {
+		return
+	}
+	face := basicfont.Face7x13
+	ts := TextSize(text, face)
+	height := ts.Y + 2
+	width := ts.X + 6
+	if width < height {
+		width = height
+	}
+	b := dst.Rect
+	r := image.Rect(b.Max.X-width, b.Min.Y, b.Max.X, b.Min.Y+height)
+	radius := height / 2
+
+	// 两端半圆加中间矩形组成胶囊形状
+	m := newMask(r)
+	stampDisc(m, image.Pt(r.Min.X+radius, r.Min.Y+radius), radius)
+	stampDisc(m, image.Pt(r.Max.X-1-radius, r.Min.Y+radius), radius)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X + radius; x < r.Max.X-radius; x++ {
+			m.Pix[m.PixOffset(x, y)] = 0xff
+		}
+	}
+	fillMask(dst, m, bg)
+	DrawText(dst, image.Pt(r.Min.X+(width-ts.X+1)/2, r.Min.Y+1), text, face, fg)
+}
@@ -0,0 +1,110 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestBadgeText(t *testing.T) {
+	tests := []struct {
+		count int
+		want  string
+	}{
+		{-1, ""},
+		{0, ""},
+		{1, "1"},
+		{99, "99"},
+		{100, "99+"},
+		{12345, "99+"},
+	}
+	for _, tt := range tests {
+		if got := BadgeText(tt.count); got != tt.want {
+			t.Errorf("BadgeText(%d) = %q，期望 %q", tt.count, got, tt.want)
+		}
+	}
+}
+
+// badgeLeft 角标最左侧被修改的列，没有修改时返回 -1
+func badgeLeft(base, img *image.RGBA) int {
+	for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+		for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+			if img.RGBAAt(x, y) != base.RGBAAt(x, y) {
+				return x
+			}
+		}
+	}
+	return -1
+}
+
+func TestDrawBadge(t *testing.T) {
+	base := whiteImage(32, 32)
+
+	// 数量为 0 时没有角标，图标保持不变
+	img := whiteImage(32, 32)
+	DrawBadge(img, BadgeText(0), red, white)
+	if !bytes.Equal(img.Pix, base.Pix) {
+		t.Error("数量为 0 时图标不应被修改")
+	}
+
+	one := whiteImage(32, 32)
+	DrawBadge(one, BadgeText(1), red, white)
+	many := whiteImage(32, 32)
+	DrawBadge(many, BadgeText(1000), red, white)
+
+	left1, leftMany := badgeLeft(base, one), badgeLeft(base, many)
+	if left1 < 0 || leftMany < 0 {
+		t.Fatal("数量大于 0 时应绘制角标")
+	}
+	if leftMany >= left1 {
+		t.Errorf("\"99+\" 角标左边界 %d 应比 \"1\" 的 %d 更靠左", leftMany, left1)
+	}
+	// 角标在右上角，底部保持原样
+	if got := rgbaAt(many, 31, 31); got != white {
+		t.Errorf("右下角像素 = %v，角标不应画到底部", got)
+	}
+	if got := rgbaAt(many, 31, 6); got != red {
+		t.Errorf("角标右端像素 = %v，期望背景色", got)
+	}
+}
+
+func TestBadgeIconEncodes(t *testing.T) {
+	img := whiteImage(32, 32)
+	DrawBadge(img, BadgeText(150), red, white)
+
+	var ico bytes.Buffer
+	if err := EncodeICO(&ico, img); err != nil {
+		t.Fatalf("EncodeICO 出错: %v", err)
+	}
+	decoded, err := DecodeIcon(ico.Bytes())
+	if err != nil {
+		t.Fatalf("DecodeIcon 出错: %v", err)
+	}
+	assertSameImage(t, "ICO", img, decoded)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode 出错: %v", err)
+	}
+	decoded, err = png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode 出错: %v", err)
+	}
+	assertSameImage(t, "PNG", img, decoded)
+}
+
+func assertSameImage(t *testing.T, name string, want *image.RGBA, got image.Image) {
+	t.Helper()
+	if got.Bounds() != want.Rect {
+		t.Fatalf("%s 解码后尺寸 = %v，期望 %v", name, got.Bounds(), want.Rect)
+	}
+	for y := want.Rect.Min.Y; y < want.Rect.Max.Y; y++ {
+		for x := want.Rect.Min.X; x < want.Rect.Max.X; x++ {
+			if g := color.NRGBAModel.Convert(got.At(x, y)); g != rgbaAt(want, x, y) {
+				t.Fatalf("%s 解码后 (%d,%d) = %v，期望 %v", name, x, y, g, rgbaAt(want, x, y))
+			}
+		}
+	}
+}
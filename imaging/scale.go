@@ -0,0 +1,151 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// Filter 缩放采样方式
+type Filter int
+
+const (
+	FilterAuto       Filter = iota // 缩小用 Catmull-Rom，放大用最近邻（便于查看像素）
+	FilterNearest                  // 最近邻
+	FilterBilinear                 // 双线性
+	FilterCatmullRom               // Catmull-Rom 三次插值
+)
+
+// String 返回采样方式名称
+func (f Filter) String() string {
+	switch f {
+	case FilterNearest:
+		return "nearest"
+	case FilterBilinear:
+		return "bilinear"
+	case FilterCatmullRom:
+		return "catmull-rom"
+	}
+	return "auto"
+}
+
+// resolve 按缩放比例确定实际使用的采样方式
+func (f Filter) resolve(scale float64) Filter {
+	if f != FilterAuto {
+		return f
+	}
+	if scale < 1 {
+		return FilterCatmullRom
+	}
+	return FilterNearest
+}
+
+func (f Filter) interpolator() xdraw.Interpolator {
+	switch f {
+	case FilterBilinear:
+		return xdraw.BiLinear
+	case FilterCatmullRom:
+		return xdraw.CatmullRom
+	}
+	return xdraw.NearestNeighbor
+}
+
+// Resize 将图片缩放到 w×h
+//
+// 缩小超过一半时先逐级 2×2 平均减半，再做最后一次插值，
+// 这样 4K 截图缩成缩略图也只需对少量像素做卷积，且不会出现摩尔纹。
+func Resize(src image.Image, w, h int, filter Filter) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if w <= 0 || h <= 0 {
+		return dst
+	}
+	filter = filter.resolve(float64(w) / float64(src.Bounds().Dx()))
+	if filter != FilterNearest {
+		for src.Bounds().Dx() >= 2*w && src.Bounds().Dy() >= 2*h {
+			src = halve(src)
+		}
+	}
+	filter.interpolator().Scale(dst, dst.Rect, src, src.Bounds(), xdraw.Src, nil)
+	return dst
+}
+
+// halve 2×2 平均缩小一半
+func halve(src image.Image) *image.RGBA {
+	rgba, ok := src.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(src.Bounds())
+		draw.Draw(rgba, rgba.Rect, src, rgba.Rect.Min, draw.Src)
+	}
+	b := rgba.Rect
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx()/2, b.Dy()/2))
+	for y := 0; y < dst.Rect.Dy(); y++ {
+		r0 := rgba.Pix[rgba.PixOffset(b.Min.X, b.Min.Y+2*y):]
+		r1 := rgba.Pix[rgba.PixOffset(b.Min.X, b.Min.Y+2*y+1):]
+		out := dst.Pix[y*dst.Stride:]
+		for x := 0; x < dst.Rect.Dx(); x++ {
+			i := x * 8
+			for c := 0; c < 4; c++ {
+				sum := int(r0[i+c]) + int(r0[i+4+c]) + int(r1[i+c]) + int(r1[i+4+c])
+				out[x*4+c] = uint8((sum + 2) / 4)
+			}
+		}
+	}
+	return dst
+}
+
+// Renderer 按视图渲染图片，缓存缩小后的整张图片
+//
+// 缓存只在图片、缩放比例或采样方式变化时重新生成，平移与重绘只需拷贝；
+// 放大时只插值可见部分，因此无论放大多少倍开销都与显示区域大小相当。
+type Renderer struct {
+	Filter Filter
+
+	src    image.Image
+	scale  float64
+	filter Filter
+	cache  *image.RGBA
+}
+
+// Reset 丢弃缓存，图片内容原地修改后需要调用
+func (r *Renderer) Reset() {
+	r.src, r.cache = nil, nil
+}
+
+// Render 将 src 按视图 v 绘制到 dst，图片之外填充 background
+func (r *Renderer) Render(dst *image.RGBA, v *View, src image.Image, background color.RGBA) {
+	scale := v.Scale()
+	filter := r.Filter.resolve(scale)
+	if filter == FilterNearest || scale == 1 {
+		v.Render(dst, src, background)
+		return
+	}
+
+	draw.Draw(dst, dst.Rect, &image.Uniform{C: background}, image.Point{}, draw.Src)
+	imageRect := v.ImageRect()
+	visible := imageRect.Intersect(dst.Rect)
+	if visible.Empty() {
+		return
+	}
+
+	if scale < 1 {
+		if r.cache == nil || r.src != src || r.scale != scale || r.filter != filter {
+			size := v.ImageSize()
+			w := int(math.Ceil(float64(size.X) * scale))
+			h := int(math.Ceil(float64(size.Y) * scale))
+			r.cache = Resize(src, w, h, filter)
+			r.src, r.scale, r.filter = src, scale, filter
+		}
+		draw.Draw(dst, visible, r.cache, visible.Min.Sub(imageRect.Min), draw.Src)
+		return
+	}
+
+	// 放大：只插值覆盖可见区域的源像素，目标矩形按像素边界对齐
+	p0, _ := v.ToImage(visible.Min.X, visible.Min.Y)
+	p1, _ := v.ToImage(visible.Max.X-1, visible.Max.Y-1)
+	srcRect := image.Rectangle{Min: p0, Max: p1.Add(image.Pt(1, 1))}.Intersect(image.Rectangle{Max: v.ImageSize()})
+	dstRect := image.Rectangle{Min: v.ToView(srcRect.Min), Max: v.ToView(srcRect.Max)}
+	filter.interpolator().Scale(dst, dstRect, src, srcRect.Add(src.Bounds().Min), xdraw.Src, nil)
+}
@@ -0,0 +1,147 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Selection 拖拽选区，坐标均为图片坐标
+type Selection struct {
+	bounds  image.Rectangle // 选区允许的范围
+	anchor  image.Point     // 按下时的位置
+	current image.Point     // 当前位置
+	active  bool            // 是否正在拖拽
+	done    bool            // 是否已完成一次拖拽
+}
+
+// NewSelection 创建限制在 bounds 内的选区
+func NewSelection(bounds image.Rectangle) *Selection {
+	return &Selection{bounds: bounds}
+}
+
+// Bounds 选区允许的范围
+func (s *Selection) Bounds() image.Rectangle {
+	return s.bounds
+}
+
+// Begin 开始拖拽
+func (s *Selection) Begin(p image.Point) {
+	p = ClampPoint(p, s.bounds)
+	s.anchor, s.current = p, p
+	s.active = true
+	s.done = false
+}
+
+// Update 拖拽到新的位置
+func (s *Selection) Update(p image.Point) {
+	if !s.active {
+		return
+	}
+	s.current = ClampPoint(p, s.bounds)
+}
+
+// End 结束拖拽，返回最终选区
+func (s *Selection) End(p image.Point) image.Rectangle {
+	s.Update(p)
+	if s.active {
+		s.active = false
+		s.done = true
+	}
+	return s.Rect()
+}
+
+// Reset 清除选区
+func (s *Selection) Reset() {
+	*s = Selection{bounds: s.bounds}
+}
+
+// Active 是否正在拖拽
+func (s *Selection) Active() bool {
+	return s.active
+}
+
+// Done 是否已完成拖拽且选区非空
+func (s *Selection) Done() bool {
+	return s.done && !s.Rect().Empty()
+}
+
+// Rect 当前选区（已规范化并裁剪到范围内），未开始时为空
+func (s *Selection) Rect() image.Rectangle {
+	if !s.active && !s.done {
+		return image.Rectangle{}
+	}
+	return image.Rectangle{Min: s.anchor, Max: s.current}.Canon().Intersect(s.bounds)
+}
+
+// SizeText 选区尺寸读数，如 "320 × 200"
+func (s *Selection) SizeText() string {
+	r := s.Rect()
+	return fmt.Sprintf("%d × %d", r.Dx(), r.Dy())
+}
+
+// ClampPoint 将点限制在矩形内（Max 边界包含在内，便于选到最后一行/列）
+func ClampPoint(p image.Point, r image.Rectangle) image.Point {
+	if p.X < r.Min.X {
+		p.X = r.Min.X
+	}
+	if p.X > r.Max.X {
+		p.X = r.Max.X
+	}
+	if p.Y < r.Min.Y {
+		p.Y = r.Min.Y
+	}
+	if p.Y > r.Max.Y {
+		p.Y = r.Max.Y
+	}
+	return p
+}
+
+// Crop 复制图片中的矩形区域，结果以 (0,0) 为原点
+func Crop(img image.Image, r image.Rectangle) (*image.RGBA, error) {
+	r = r.Intersect(img.Bounds())
+	if r.Empty() {
+		return nil, fmt.Errorf("裁剪区域为空")
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, r.Min, draw.Src)
+	return dst, nil
+}
+
+// Dim 生成变暗的图片副本，factor 为保留的亮度比例（0~1）
+func Dim(img image.Image, factor float64) *image.RGBA {
+	if factor < 0 {
+		factor = 0
+	}
+	if factor > 1 {
+		factor = 1
+	}
+	b := img.Bounds()
+	dst := image.NewRGBA(b)
+	draw.Draw(dst, b, img, b.Min, draw.Src)
+	for i := 0; i < len(dst.Pix); i += 4 {
+		dst.Pix[i+0] = uint8(float64(dst.Pix[i+0]) * factor)
+		dst.Pix[i+1] = uint8(float64(dst.Pix[i+1]) * factor)
+		dst.Pix[i+2] = uint8(float64(dst.Pix[i+2]) * factor)
+		dst.Pix[i+3] = 255
+	}
+	return dst
+}
+
+// Magnify 取以 center 为中心、边长 2*radius+1 的像素网格，超出图片的部分为透明
+func Magnify(img image.Image, center image.Point, radius int) [][]color.RGBA {
+	b := img.Bounds()
+	size := 2*radius + 1
+	grid := make([][]color.RGBA, size)
+	for dy := 0; dy < size; dy++ {
+		grid[dy] = make([]color.RGBA, size)
+		for dx := 0; dx < size; dx++ {
+			p := image.Pt(center.X-radius+dx, center.Y-radius+dy)
+			if p.In(b) {
+				grid[dy][dx] = color.RGBAModel.Convert(img.At(p.X, p.Y)).(color.RGBA)
+			}
+		}
+	}
+	return grid
+}
@@ -0,0 +1,132 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestSelectionDrag(t *testing.T) {
+	bounds := image.Rect(0, 0, 100, 80)
+	tests := []struct {
+		name     string
+		from, to image.Point
+		want     image.Rectangle
+	}{
+		{"向右下拖拽", image.Pt(10, 10), image.Pt(50, 40), image.Rect(10, 10, 50, 40)},
+		{"向左上拖拽规范化", image.Pt(50, 40), image.Pt(10, 10), image.Rect(10, 10, 50, 40)},
+		{"超出范围被裁剪", image.Pt(-20, -5), image.Pt(150, 200), bounds},
+		{"拖到最后一行列", image.Pt(90, 70), image.Pt(100, 80), image.Rect(90, 70, 100, 80)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSelection(bounds)
+			s.Begin(tt.from)
+			if !s.Active() {
+				t.Fatal("Begin 后应处于拖拽中")
+			}
+			if got := s.End(tt.to); got != tt.want {
+				t.Errorf("End() = %v，期望 %v", got, tt.want)
+			}
+			if s.Active() || !s.Done() {
+				t.Errorf("End 后 Active=%v Done=%v", s.Active(), s.Done())
+			}
+		})
+	}
+}
+
+func TestSelectionNonZeroOrigin(t *testing.T) {
+	// 多显示器截图的坐标可以为负
+	s := NewSelection(image.Rect(-1920, 0, 0, 1080))
+	s.Begin(image.Pt(-100, 100))
+	s.Update(image.Pt(-300, 50))
+	if got, want := s.Rect(), image.Rect(-300, 50, -100, 100); got != want {
+		t.Errorf("Rect() = %v，期望 %v", got, want)
+	}
+	if got := s.SizeText(); got != "200 × 50" {
+		t.Errorf("SizeText() = %q", got)
+	}
+}
+
+func TestSelectionEmptyAndReset(t *testing.T) {
+	s := NewSelection(image.Rect(0, 0, 10, 10))
+	if !s.Rect().Empty() || s.Done() {
+		t.Fatal("未开始时选区应为空")
+	}
+
+	// 未开始拖拽时 Update 无效
+	s.Update(image.Pt(5, 5))
+	if !s.Rect().Empty() {
+		t.Errorf("未开始拖拽时 Update 不应产生选区，得到 %v", s.Rect())
+	}
+
+	// 单击得到零面积选区，不算完成
+	s.Begin(image.Pt(3, 3))
+	s.End(image.Pt(3, 3))
+	if s.Done() {
+		t.Error("零面积选区不应算作完成")
+	}
+
+	s.Begin(image.Pt(1, 1))
+	s.End(image.Pt(4, 4))
+	s.Reset()
+	if s.Active() || s.Done() || !s.Rect().Empty() {
+		t.Error("Reset 后应清除选区")
+	}
+	if s.Bounds() != image.Rect(0, 0, 10, 10) {
+		t.Error("Reset 不应改变范围")
+	}
+}
+
+func TestClampPoint(t *testing.T) {
+	r := image.Rect(0, 0, 10, 10)
+	tests := []struct {
+		in, want image.Point
+	}{
+		{image.Pt(5, 5), image.Pt(5, 5)},
+		{image.Pt(-1, 5), image.Pt(0, 5)},
+		{image.Pt(11, 12), image.Pt(10, 10)},
+		{image.Pt(10, 10), image.Pt(10, 10)}, // Max 边界包含在内
+	}
+	for _, tt := range tests {
+		if got := ClampPoint(tt.in, r); got != tt.want {
+			t.Errorf("ClampPoint(%v) = %v，期望 %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCrop(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	src.Set(2, 1, color.RGBA{R: 255, A: 255})
+
+	got, err := Crop(src, image.Rect(2, 1, 10, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Bounds() != image.Rect(0, 0, 2, 3) {
+		t.Errorf("Bounds() = %v，期望裁剪到图片范围并以 (0,0) 为原点", got.Bounds())
+	}
+	if c := got.RGBAAt(0, 0); c.R != 255 {
+		t.Errorf("左上角像素 = %v", c)
+	}
+
+	if _, err := Crop(src, image.Rect(5, 5, 8, 8)); err == nil {
+		t.Error("区域在图片外时应返回错误")
+	}
+}
+
+func TestMagnify(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 3, 3))
+	src.Set(0, 0, color.RGBA{G: 255, A: 255})
+
+	grid := Magnify(src, image.Pt(0, 0), 1)
+	if len(grid) != 3 || len(grid[0]) != 3 {
+		t.Fatalf("网格尺寸 %dx%d", len(grid), len(grid[0]))
+	}
+	if grid[1][1].G != 255 {
+		t.Errorf("中心像素 = %v", grid[1][1])
+	}
+	if grid[0][0] != (color.RGBA{}) {
+		t.Errorf("图片外的像素应透明，得到 %v", grid[0][0])
+	}
+}
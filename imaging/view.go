@@ -0,0 +1,187 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// 缩放范围
+const (
+	MinZoom = 0.05
+	MaxZoom = 32.0
+)
+
+// View 图片在显示区域中的视图变换：视图坐标 = Origin + 图片坐标 × Scale
+//
+// 图片坐标以图片左上角为 (0, 0)。处于适应模式时，调整大小会重新适应。
+type View struct {
+	imageSize image.Point
+	viewSize  image.Point
+	scale     float64
+	originX   float64
+	originY   float64
+	fit       bool
+}
+
+// NewView 创建适应显示区域的视图
+func NewView(imageSize, viewSize image.Point) *View {
+	v := &View{imageSize: imageSize, viewSize: viewSize}
+	v.Fit()
+	return v
+}
+
+// Scale 当前缩放比例
+func (v *View) Scale() float64 {
+	return v.scale
+}
+
+// Fitted 是否处于适应模式
+func (v *View) Fitted() bool {
+	return v.fit
+}
+
+// ImageSize 图片尺寸
+func (v *View) ImageSize() image.Point {
+	return v.imageSize
+}
+
+// SetImageSize 更换图片；尺寸变化时重新适应
+func (v *View) SetImageSize(size image.Point) {
+	if size == v.imageSize {
+		return
+	}
+	v.imageSize = size
+	v.Fit()
+}
+
+// Resize 调整显示区域大小
+func (v *View) Resize(viewSize image.Point) {
+	v.viewSize = viewSize
+	if v.fit {
+		v.Fit()
+	} else {
+		v.clamp()
+	}
+}
+
+// FitScale 完整显示图片所需的比例；图片比显示区域小时不放大
+func (v *View) FitScale() float64 {
+	if v.imageSize.X <= 0 || v.imageSize.Y <= 0 {
+		return 1
+	}
+	s := math.Min(float64(v.viewSize.X)/float64(v.imageSize.X), float64(v.viewSize.Y)/float64(v.imageSize.Y))
+	return math.Min(s, 1)
+}
+
+// Fit 缩放到完整显示并居中
+func (v *View) Fit() {
+	v.scale = v.FitScale()
+	v.fit = true
+	v.clamp()
+}
+
+// ActualSize 以 1:1 显示，保持显示区域中心对应的图片位置不变
+func (v *View) ActualSize() {
+	v.ZoomAt(float64(v.viewSize.X)/2, float64(v.viewSize.Y)/2, 1/v.scale)
+}
+
+// ToggleFit 在适应与 1:1 之间切换
+func (v *View) ToggleFit() {
+	if v.fit && v.scale != 1 {
+		v.ActualSize()
+	} else {
+		v.Fit()
+	}
+}
+
+// ZoomAt 以视图坐标 (x, y) 为中心缩放 factor 倍，该点下的图片像素保持不动
+func (v *View) ZoomAt(x, y, factor float64) {
+	scale := math.Max(MinZoom, math.Min(MaxZoom, v.scale*factor))
+	ix, iy := (x-v.originX)/v.scale, (y-v.originY)/v.scale
+	v.scale = scale
+	v.originX = x - ix*scale
+	v.originY = y - iy*scale
+	v.fit = false
+	v.clamp()
+}
+
+// Pan 平移视图
+func (v *View) Pan(dx, dy float64) {
+	v.originX += dx
+	v.originY += dy
+	v.clamp()
+}
+
+// clamp 图片小于显示区域时居中，否则不允许拖出空白
+func (v *View) clamp() {
+	v.originX = clampAxis(v.originX, float64(v.imageSize.X)*v.scale, float64(v.viewSize.X))
+	v.originY = clampAxis(v.originY, float64(v.imageSize.Y)*v.scale, float64(v.viewSize.Y))
+}
+
+func clampAxis(origin, scaled, view float64) float64 {
+	if scaled <= view {
+		return math.Floor((view - scaled) / 2)
+	}
+	return math.Max(view-scaled, math.Min(0, origin))
+}
+
+// ToImage 视图坐标转换为图片像素坐标，ok 表示是否落在图片内
+func (v *View) ToImage(x, y int) (p image.Point, ok bool) {
+	p = image.Pt(
+		int(math.Floor((float64(x)-v.originX)/v.scale)),
+		int(math.Floor((float64(y)-v.originY)/v.scale)),
+	)
+	return p, p.In(image.Rectangle{Max: v.imageSize})
+}
+
+// ToView 图片坐标转换为视图坐标（像素左上角）
+func (v *View) ToView(p image.Point) image.Point {
+	return image.Pt(
+		int(math.Floor(v.originX+float64(p.X)*v.scale)),
+		int(math.Floor(v.originY+float64(p.Y)*v.scale)),
+	)
+}
+
+// ImageRect 图片在视图中占据的矩形
+func (v *View) ImageRect() image.Rectangle {
+	return image.Rectangle{Min: v.ToView(image.Point{}), Max: v.ToView(v.imageSize)}
+}
+
+// Render 以最近邻采样将 src 的可见部分绘制到 dst，图片之外填充 background
+func (v *View) Render(dst *image.RGBA, src image.Image, background color.RGBA) {
+	draw.Draw(dst, dst.Rect, &image.Uniform{C: background}, image.Point{}, draw.Src)
+	visible := v.ImageRect().Intersect(dst.Rect)
+	if visible.Empty() {
+		return
+	}
+
+	sb := src.Bounds()
+	rgba, _ := src.(*image.RGBA)
+	// 预先计算每一列对应的源像素
+	cols := make([]int, visible.Dx())
+	for i := range cols {
+		sx := int((float64(visible.Min.X+i)+0.5-v.originX)/v.scale) + sb.Min.X
+		if sx >= sb.Max.X {
+			sx = sb.Max.X - 1
+		}
+		cols[i] = sx
+	}
+
+	for y := visible.Min.Y; y < visible.Max.Y; y++ {
+		sy := int((float64(y)+0.5-v.originY)/v.scale) + sb.Min.Y
+		if sy >= sb.Max.Y {
+			sy = sb.Max.Y - 1
+		}
+		row := dst.Pix[dst.PixOffset(visible.Min.X, y):]
+		for i, sx := range cols {
+			if rgba != nil {
+				copy(row[i*4:i*4+4], rgba.Pix[rgba.PixOffset(sx, sy):])
+				continue
+			}
+			c := color.RGBAModel.Convert(src.At(sx, sy)).(color.RGBA)
+			row[i*4], row[i*4+1], row[i*4+2], row[i*4+3] = c.R, c.G, c.B, c.A
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestViewFit(t *testing.T) {
+	v := NewView(image.Pt(400, 200), image.Pt(200, 200))
+	if !v.Fitted() || v.Scale() != 0.5 {
+		t.Fatalf("Fitted=%v Scale=%v，期望适应模式 0.5", v.Fitted(), v.Scale())
+	}
+	if got, want := v.ImageRect(), image.Rect(0, 50, 200, 150); got != want {
+		t.Errorf("ImageRect() = %v，期望垂直居中 %v", got, want)
+	}
+
+	// 图片比显示区域小时不放大，居中显示
+	v = NewView(image.Pt(50, 50), image.Pt(200, 200))
+	if v.Scale() != 1 {
+		t.Errorf("Scale() = %v，小图不应放大", v.Scale())
+	}
+	if got, want := v.ImageRect(), image.Rect(75, 75, 125, 125); got != want {
+		t.Errorf("ImageRect() = %v，期望 %v", got, want)
+	}
+
+	// 适应模式下调整大小会重新适应
+	v = NewView(image.Pt(400, 200), image.Pt(200, 200))
+	v.Resize(image.Pt(100, 100))
+	if v.Scale() != 0.25 {
+		t.Errorf("Resize 后 Scale() = %v，期望 0.25", v.Scale())
+	}
+}
+
+func TestViewZoomAtKeepsPoint(t *testing.T) {
+	v := NewView(image.Pt(1000, 1000), image.Pt(500, 500))
+	before, ok := v.ToImage(100, 120)
+	if !ok {
+		t.Fatal("缩放前的点应在图片内")
+	}
+
+	v.ZoomAt(100, 120, 4)
+	if v.Scale() != 2 || v.Fitted() {
+		t.Fatalf("Scale=%v Fitted=%v，期望 2 且退出适应模式", v.Scale(), v.Fitted())
+	}
+	if after, _ := v.ToImage(100, 120); after != before {
+		t.Errorf("缩放中心下的像素 = %v，期望保持 %v", after, before)
+	}
+
+	v.ZoomAt(100, 120, 0.5)
+	if after, _ := v.ToImage(100, 120); after != before {
+		t.Errorf("缩小后中心下的像素 = %v，期望保持 %v", after, before)
+	}
+}
+
+func TestViewZoomLimits(t *testing.T) {
+	v := NewView(image.Pt(100, 100), image.Pt(100, 100))
+	v.ZoomAt(50, 50, 1e6)
+	if v.Scale() != MaxZoom {
+		t.Errorf("Scale() = %v，期望限制在 MaxZoom", v.Scale())
+	}
+	v.ZoomAt(50, 50, 1e-9)
+	if v.Scale() != MinZoom {
+		t.Errorf("Scale() = %v，期望限制在 MinZoom", v.Scale())
+	}
+}
+
+func TestViewPanClamp(t *testing.T) {
+	view := image.Pt(500, 500)
+	v := NewView(image.Pt(1000, 1000), view)
+	v.ZoomAt(250, 250, 4) // 2000×2000，大于显示区域
+
+	v.Pan(1e5, 1e5)
+	if got := v.ImageRect().Min; got != (image.Point{}) {
+		t.Errorf("向右下拖到底后左上角 = %v，不应露出空白", got)
+	}
+	v.Pan(-1e5, -1e5)
+	if got := v.ImageRect().Max; got != view {
+		t.Errorf("向左上拖到底后右下角 = %v，期望 %v", got, view)
+	}
+
+	// 缩小到比显示区域小时重新居中，平移无效
+	v.ZoomAt(250, 250, 0.1)
+	rect := v.ImageRect()
+	v.Pan(30, -30)
+	if v.ImageRect() != rect {
+		t.Errorf("图片小于显示区域时平移应无效，%v → %v", rect, v.ImageRect())
+	}
+	if rect.Min.X != view.X-rect.Max.X {
+		t.Errorf("图片 %v 应水平居中", rect)
+	}
+}
+
+func TestViewToImageToView(t *testing.T) {
+	v := NewView(image.Pt(400, 200), image.Pt(200, 200)) // 比例 0.5，原点 (0, 50)
+	tests := []struct {
+		x, y int
+		want image.Point
+		ok   bool
+	}{
+		{0, 50, image.Pt(0, 0), true},
+		{199, 149, image.Pt(398, 198), true},
+		{10, 10, image.Pt(20, -80), false},  // 上方空白
+		{10, 150, image.Pt(20, 200), false}, // 下方空白，Max 不在图片内
+	}
+	for _, tt := range tests {
+		got, ok := v.ToImage(tt.x, tt.y)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ToImage(%d, %d) = %v, %v，期望 %v, %v", tt.x, tt.y, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	v.ZoomAt(0, 50, 6) // 比例 3
+	for _, p := range []image.Point{{0, 0}, {7, 3}, {399, 199}} {
+		vp := v.ToView(p)
+		for _, d := range []image.Point{{0, 0}, {2, 2}} {
+			if got, _ := v.ToImage(vp.X+d.X, vp.Y+d.Y); got != p {
+				t.Errorf("ToImage(ToView(%v)+%v) = %v", p, d, got)
+			}
+		}
+		if got, _ := v.ToImage(vp.X+3, vp.Y); got != p.Add(image.Pt(1, 0)) {
+			t.Errorf("ToView(%v) 右侧第 3 个像素应属于下一列，得到 %v", p, got)
+		}
+	}
+}
+
+func TestViewToggleFit(t *testing.T) {
+	v := NewView(image.Pt(400, 400), image.Pt(200, 200))
+	v.ToggleFit()
+	if v.Scale() != 1 || v.Fitted() {
+		t.Fatalf("第一次切换后 Scale=%v Fitted=%v，期望 1:1", v.Scale(), v.Fitted())
+	}
+	v.ToggleFit()
+	if v.Scale() != 0.5 || !v.Fitted() {
+		t.Errorf("第二次切换后 Scale=%v Fitted=%v，期望回到适应", v.Scale(), v.Fitted())
+	}
+}
+
+func TestViewRender(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	colors := [2][2]color.RGBA{
+		{{R: 255, A: 255}, {G: 255, A: 255}},
+		{{B: 255, A: 255}, {R: 255, G: 255, A: 255}},
+	}
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			src.SetRGBA(x, y, colors[y][x])
+		}
+	}
+	bg := color.RGBA{A: 255}
+	dst := image.NewRGBA(image.Rect(0, 0, 4, 4))
+
+	// 1:1 居中，四周是背景
+	v := NewView(src.Rect.Size(), dst.Rect.Size())
+	v.Render(dst, src, bg)
+	if got := dst.RGBAAt(0, 0); got != bg {
+		t.Errorf("图片外像素 = %v，期望背景色", got)
+	}
+	if got := dst.RGBAAt(2, 1); got != colors[0][1] {
+		t.Errorf("(2,1) = %v，期望 %v", got, colors[0][1])
+	}
+
+	// 放大 2 倍后每个源像素占 2×2
+	v.ZoomAt(2, 2, 2)
+	v.Render(dst, src, bg)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if got, want := dst.RGBAAt(x, y), colors[y/2][x/2]; got != want {
+				t.Errorf("(%d,%d) = %v，期望 %v", x, y, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math/bits"
+)
+
+// webpMaxSize VP8L 单边最大像素数
+const webpMaxSize = 1 << 14
+
+// codeLengthOrder VP8L 码长码的写出顺序
+var codeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// EncodeWebPLossless 编码为无损 WebP（VP8L）
+//
+// 不做预测变换与 LZ77，每个通道使用固定的 8 位前缀码，
+// 文件体积与未压缩位图相当，但任何 WebP 解码器都能无损还原。
+func EncodeWebPLossless(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	if b.Dx() <= 0 || b.Dy() <= 0 || b.Dx() > webpMaxSize || b.Dy() > webpMaxSize {
+		return fmt.Errorf("WebP 尺寸 %dx%d 超出范围（1-%d）", b.Dx(), b.Dy(), webpMaxSize)
+	}
+
+	bw := &bitWriter{}
+	bw.write(0x2f, 8) // VP8L 签名
+	bw.write(uint32(b.Dx()-1), 14)
+	bw.write(uint32(b.Dy()-1), 14)
+	bw.write(1, 1) // 可能含透明度
+	bw.write(0, 3) // 版本
+	bw.write(0, 1) // 无变换
+	bw.write(0, 1) // 无颜色缓存
+	bw.write(0, 1) // 无元前缀码
+
+	// 绿色（含长度前缀与缓存符号，共 280 个）、红、蓝、透明度：0-255 码长均为 8
+	bw.writeByteCode(256 + 24)
+	bw.writeByteCode(256)
+	bw.writeByteCode(256)
+	bw.writeByteCode(256)
+	// 距离码：只有一个符号的简单码，不占位
+	bw.write(1, 1) // 简单码
+	bw.write(0, 1) // 1 个符号
+	bw.write(0, 1) // 符号用 1 位表示
+	bw.write(0, 1) // 符号 0
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			// 前缀码按位从高到低读取，而位流从低位开始写
+			bw.write(uint32(bits.Reverse8(c.G)), 8)
+			bw.write(uint32(bits.Reverse8(c.R)), 8)
+			bw.write(uint32(bits.Reverse8(c.B)), 8)
+			bw.write(uint32(bits.Reverse8(c.A)), 8)
+		}
+	}
+	data := bw.bytes()
+
+	chunkSize := len(data)
+	padded := chunkSize + chunkSize%2
+	header := make([]byte, 0, 20)
+	header = append(header, "RIFF"...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(4+8+padded))
+	header = append(header, "WEBP"...)
+	header = append(header, "VP8L"...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(chunkSize))
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if chunkSize%2 == 1 {
+		data = append(data, 0)
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// bitWriter VP8L 位流，低位在前
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits uint
+}
+
+func (bw *bitWriter) write(v uint32, n uint) {
+	bw.acc |= uint64(v) << bw.nbits
+	bw.nbits += n
+	for bw.nbits >= 8 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc >>= 8
+		bw.nbits -= 8
+	}
+}
+
+func (bw *bitWriter) bytes() []byte {
+	if bw.nbits > 0 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc, bw.nbits = 0, 0
+	}
+	return bw.buf
+}
+
+// writeByteCode 写出符号 0-255 码长为 8、其余符号不使用的前缀码
+//
+// 码长本身用只含符号 0 与 8 的码长码编码，两者各占 1 位。
+func (bw *bitWriter) writeByteCode(alphabet int) {
+	bw.write(0, 1) // 普通码
+	// 码长码需要写到符号 8 在顺序表中的位置
+	num := 0
+	for i, sym := range codeLengthOrder {
+		if sym == 8 {
+			num = i + 1
+		}
+	}
+	bw.write(uint32(num-4), 4)
+	for _, sym := range codeLengthOrder[:num] {
+		if sym == 0 || sym == 8 {
+			bw.write(1, 3)
+		} else {
+			bw.write(0, 3)
+		}
+	}
+	bw.write(0, 1) // 码长覆盖整个字母表
+	for i := 0; i < alphabet; i++ {
+		if i < 256 {
+			bw.write(1, 1) // 码长 8
+		} else {
+			bw.write(0, 1) // 码长 0
+		}
+	}
+}
@@ -1,20 +1,35 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"image"
 	"log"
+	"os"
+	"time"
 
 	"github.com/package-register/gui/event"
 	"github.com/package-register/gui/sdk"
 )
 
 func main() {
+	// 键位配置：存在 keymap.json 时覆盖默认快捷键，如 {"tab.next": "Ctrl+PageDown"}
+	var keymap map[string]string
+	if f, err := os.Open("keymap.json"); err == nil {
+		if keymap, err = sdk.LoadKeymap(f); err != nil {
+			log.Printf("键位配置无效: %v", err)
+		}
+		f.Close()
+	}
+
 	app := sdk.New(
 		sdk.WithTitle("oAo Agent - Team"),
 		sdk.WithSize(800, 600),
 		sdk.WithTray("oAo Agent - Team", nil),
 		sdk.WithHideConsole(), // 隐藏控制台窗口（仅对编译后的exe有效）
+		sdk.WithKeymap(keymap),
+		// 演示用的识别后端：任何区域都识别出同一段文字，实际使用时替换为真正的 OCR 引擎
+		sdk.WithOCR(sdk.NewFakeRecognizer(sdk.TextBlock{Text: "示例文字", Bounds: image.Rect(0, 0, 1, 1)})),
 	)
 
 	// 注册主页Tab
@@ -22,11 +37,13 @@ func main() {
 		t.AddLabel("欢迎使用 oAo Agent - Team", 20, 10, 400, 25)
 		t.AddSeparator(20, 40, 540)
 
-		t.AddLabel("用户名:", 20, 55, 60, 25)
-		t.AddEditLine(90, 52, 200, 25)
+		_, username := t.AddLabeledEditLine("用户名:", 20, 52, 60, 200, 25)
+		t.SetDefaultFocus(username) // 切换到主页时聚焦用户名，Tab 键按添加顺序导航
 
-		t.AddLabel("备注:", 20, 90, 60, 25)
-		t.AddTextEdit(90, 87, 200, 80)
+		remarkLabel := t.AddLabel("备注:", 20, 90, 60, 25)
+		remark := t.AddTextEdit(90, 87, 200, 80)
+		t.LabelFor(remarkLabel, remark)
+		t.SetAccessible(remark, "", "可选，最多 200 字")
 
 		t.AddCheckBox("记住我", 90, 175, 100, 25, func(checked bool) {
 			log.Printf("记住我: %v", checked)
@@ -41,6 +58,9 @@ func main() {
 		pb.SetValue(0.6)
 	})
 
+	// 截图打码：遮住密码管理器等敏感窗口
+	redactor := sdk.NewRedactor(sdk.WindowDetector("KeePass", "1Password", "Bitwarden", "密码"))
+
 	// 注册截图Tab
 	app.RegisterTab("截图", func(t *sdk.TabContext) {
 		t.AddLabel("截图工具演示", 20, 10, 400, 25)
@@ -67,10 +87,72 @@ func main() {
 			log.Printf("隐藏窗口截图成功，尺寸: %dx%d", img.Bounds().Dx(), img.Bounds().Dy())
 		})
 
+		// 截取所有显示器拼接成的虚拟桌面
+		t.AddScreenshotButton("截图（全部显示器）", 340, 90, 150, 30, true, func(img image.Image, err error) {
+			if err != nil {
+				log.Printf("截图失败: %v", err)
+				return
+			}
+			log.Printf("虚拟桌面截图成功，尺寸: %dx%d，显示器数: %d", img.Bounds().Dx(), img.Bounds().Dy(), len(sdk.Displays()))
+		}, sdk.WithCaptureTarget(sdk.CaptureAllDisplays()))
+
+		// 框选区域截图
+		t.AddScreenshotButton("区域截图", 500, 90, 120, 30, true, func(img image.Image, err error) {
+			if errors.Is(err, sdk.ErrCaptureCancelled) {
+				log.Println("区域截图已取消")
+				return
+			}
+			if err != nil {
+				log.Printf("截图失败: %v", err)
+				return
+			}
+			log.Printf("区域截图成功，尺寸: %dx%d", img.Bounds().Dx(), img.Bounds().Dy())
+		}, sdk.WithCaptureTarget(sdk.CaptureAllDisplays()), sdk.WithRegionSelect())
+
+		// 框选区域并复制其中的文字，使用 sdk.WithOCR 配置的识别引擎
+		t.AddScreenshotButton("复制区域文字", 630, 90, 130, 30, true, func(img image.Image, err error) {
+			if err != nil && !errors.Is(err, sdk.ErrCaptureCancelled) {
+				log.Printf("截图失败: %v", err)
+			}
+		}, sdk.WithCaptureTarget(sdk.CaptureAllDisplays()), sdk.WithRegionSelect(), sdk.WithCopyText(nil))
+		t.Events().On(event.TextRecognized, func(e event.Event) {
+			if result, ok := e.Data.(*sdk.OCRResult); ok {
+				log.Printf("已复制 %d 段文字到剪贴板", len(result.Blocks))
+			}
+		})
+
+		// 全局快捷键：在任何程序中按 Ctrl+Shift+S 框选截图并复制到剪贴板
+		regionCapture := t.ScreenshotAction(true, func(img image.Image, err error) {
+			if err != nil && !errors.Is(err, sdk.ErrCaptureCancelled) {
+				log.Printf("截图失败: %v", err)
+			}
+		}, sdk.WithCaptureTarget(sdk.CaptureAllDisplays()), sdk.WithRegionSelect(), sdk.WithCopyToClipboard())
+		if err := app.RegisterHotkey("Ctrl+Shift+S", regionCapture); err != nil {
+			log.Printf("注册截图快捷键失败: %v", err)
+		}
+
 		// 图片显示区域
 		t.AddLabel("截图预览:", 20, 140, 100, 25)
 		imageDisplay := t.AddImage(20, 170, 400, 250)
 
+		// 缩放控制：也可用滚轮缩放、拖拽平移
+		t.AddButton("放大", 430, 170, 90, 30, imageDisplay.ZoomIn)
+		t.AddButton("缩小", 430, 205, 90, 30, imageDisplay.ZoomOut)
+		t.AddButton("适应/1:1", 430, 240, 90, 30, imageDisplay.ToggleFit)
+		t.AddShortcut("preview.zoom-in", "放大预览", "Ctrl+Plus", imageDisplay.ZoomIn)
+		t.AddShortcut("preview.zoom-out", "缩小预览", "Ctrl+Minus", imageDisplay.ZoomOut)
+		t.AddShortcut("preview.fit", "预览适应/1:1", "Ctrl+0", imageDisplay.ToggleFit)
+		t.AddButton("复制图片", 430, 275, 90, 30, func() {
+			if err := imageDisplay.CopyToClipboard(); err != nil {
+				log.Printf("复制失败: %v", err)
+			}
+		})
+		t.Events().On(event.ClipboardChanged, func(e event.Event) {
+			if change, ok := e.Data.(sdk.ClipboardChange); ok && !change.Local {
+				log.Printf("剪贴板内容已变化: %s", change.Format)
+			}
+		})
+
 		// 图片信息标签
 		imageInfoLabel := t.AddLabel("图片信息: 无", 20, 430, 400, 25)
 
@@ -98,10 +180,13 @@ func main() {
 
 					// 保存按钮
 					largeTab.AddButton("保存图片", 460, 520, 100, 30, func() {
-						if saveErr := largeImage.SaveToFile("screenshot_large.png"); saveErr != nil {
+						path, saveErr := largeImage.SaveAs("screenshot_{time}.png")
+						switch {
+						case errors.Is(saveErr, sdk.ErrSaveCancelled):
+						case saveErr != nil:
 							log.Printf("保存失败: %v", saveErr)
-						} else {
-							log.Println("图片已保存为: screenshot_large.png")
+						default:
+							log.Printf("图片已保存为: %s", path)
 						}
 					})
 				})
@@ -124,7 +209,7 @@ func main() {
 			bounds := img.Bounds()
 			info := fmt.Sprintf("图片尺寸: %dx%d", bounds.Dx(), bounds.Dy())
 			imageInfoLabel.SetText(info)
-		})
+		}, sdk.WithRedaction(redactor))
 
 		// 保存截图
 		t.AddScreenshotButton("截图并保存", 150, 440, 120, 30, true, func(img image.Image, err error) {
@@ -133,12 +218,12 @@ func main() {
 				return
 			}
 
-			// 生成文件名
-			filename := fmt.Sprintf("screenshot_%d.png", img.Bounds().Dx()*img.Bounds().Dy())
-			if saveErr := imageDisplay.SaveToFile(filename); saveErr != nil {
+			// 按截图时间与显示器命名，重名时自动追加序号
+			path, saveErr := sdk.SaveImage(img, "screenshots/screenshot_{time}_{display}.png")
+			if saveErr != nil {
 				log.Printf("保存失败: %v", saveErr)
 			} else {
-				log.Printf("截图已保存为: %s", filename)
+				log.Printf("截图已保存为: %s", path)
 			}
 		})
 
@@ -153,6 +238,103 @@ func main() {
 				statusLabel.SetText(fmt.Sprintf("截图成功: %dx%d", img.Bounds().Dx(), img.Bounds().Dy()))
 			}
 		})
+
+		// 录屏：主显示器 5 秒 GIF
+		t.AddRecorder(430, 482, 350, 36, sdk.RecordConfig{
+			Duration: 5 * time.Second,
+			FPS:      10,
+			Format:   sdk.RecordGIF,
+			Redactor: redactor,
+		}, func(path string, err error) {
+			if err != nil {
+				log.Printf("录屏失败: %v", err)
+				return
+			}
+			log.Printf("录屏已保存为: %s", path)
+		})
+	})
+
+	// 注册标注Tab
+	app.RegisterTab("标注", func(t *sdk.TabContext) {
+		editor := t.AddAnnotationEditor(20, 10, 760, 480)
+
+		t.AddScreenshotButton("截图并标注", 20, 500, 120, 30, true, func(img image.Image, err error) {
+			if err != nil {
+				log.Printf("截图失败: %v", err)
+				return
+			}
+			editor.SetImage(img)
+		})
+
+		t.AddButton("保存标注", 150, 500, 120, 30, func() {
+			if err := editor.Display().SaveToFile("annotated.png"); err != nil {
+				log.Printf("保存失败: %v", err)
+			} else {
+				log.Println("标注图片已保存为: annotated.png")
+			}
+		})
+	})
+
+	// 注册截图对比Tab
+	app.RegisterTab("对比", func(t *sdk.TabContext) {
+		compare := t.AddCompareView(20, 10, 760, 480)
+
+		t.AddScreenshotButton("截取前图", 20, 500, 120, 30, true, func(img image.Image, err error) {
+			if err != nil {
+				log.Printf("截图失败: %v", err)
+				return
+			}
+			compare.SetBefore(img)
+		})
+
+		t.AddScreenshotButton("截取后图", 150, 500, 120, 30, true, func(img image.Image, err error) {
+			if err != nil {
+				log.Printf("截图失败: %v", err)
+				return
+			}
+			compare.SetAfter(img)
+			for i, r := range compare.Result().Regions {
+				log.Printf("变化区域 %d: %v", i+1, r)
+			}
+		})
+	})
+
+	// 注册定时截图时间线Tab（每 30 秒检查一次，画面变化超过 1% 才保存）
+	scheduler, err := sdk.NewCaptureScheduler(sdk.CaptureSchedulerConfig{
+		Schedule:  sdk.Every(30 * time.Second),
+		Dir:       "captures",
+		MinChange: 0.01,
+		MaxFiles:  200,
+		MaxAge:    24 * time.Hour,
+		Redactor:  redactor,
+	})
+	if err != nil {
+		log.Printf("定时截图不可用: %v", err)
+	} else {
+		defer scheduler.Stop()
+		app.RegisterTimelineTab("时间线", scheduler)
+	}
+
+	// AI 服务（配置 OPENAI_API_KEY 后启用）
+	var aiService *sdk.AIService
+	if apiKey := os.Getenv("OPENAI_API_KEY"); apiKey != "" {
+		aiService = sdk.NewAIService(sdk.AIServiceConfig{
+			APIKey:  apiKey,
+			BaseURL: os.Getenv("OPENAI_BASE_URL"),
+			Model:   os.Getenv("OPENAI_MODEL"),
+		})
+		defer aiService.Close()
+	}
+
+	// 注册助手Tab
+	var chatPanel *sdk.ChatPanel
+	app.RegisterTab("助手", func(t *sdk.TabContext) {
+		chatPanel = t.AddChatPanel(20, 10, 760, 520)
+		chatPanel.OnSend(chatPanel.SendInput)
+		chatPanel.SetRedactor(redactor)
+		if aiService != nil {
+			chatPanel.SetAIService(aiService)
+		}
 	})
 
 	// 注册关于Tab
@@ -174,23 +356,96 @@ func main() {
 		})
 	})
 
+	// 注册助手追踪调试Tab
+	if aiService != nil {
+		app.RegisterTraceTab("调试", aiService)
+	}
+
+	// 菜单栏：菜单项的快捷键在窗口内任意 Tab 下有效
+	app.RegisterMenu(func(m *sdk.MenuBar) {
+		exportChat := func(format sdk.ExportFormat) func() {
+			return func() {
+				if filename, err := chatPanel.ExportToFile("", format); err != nil {
+					log.Printf("导出失败: %v", err)
+				} else {
+					log.Printf("对话已导出: %s", filename)
+				}
+			}
+		}
+		file := m.AddMenu("文件(&F)")
+		file.AddItem("导出对话(&E)", "Ctrl+E", exportChat(sdk.ExportMarkdown))
+		exportAs := file.AddSubmenu("导出为(&X)")
+		exportAs.AddItem("Markdown", "", exportChat(sdk.ExportMarkdown))
+		exportAs.AddItem("HTML", "", exportChat(sdk.ExportHTML))
+		exportAs.AddItem("JSON", "", exportChat(sdk.ExportJSON))
+		file.AddSeparator()
+		file.AddItem("退出(&Q)", "Ctrl+Q", app.Exit)
+
+		edit := m.AddMenu("编辑(&E)")
+		edit.AddItem("复制最后一条回复(&C)", "Ctrl+Shift+C", func() {
+			if err := chatPanel.CopyLastReply(); err != nil {
+				log.Printf("复制失败: %v", err)
+			}
+		})
+		edit.AddCheckItem("附加截图前自动打码(&R)", "", true, func(checked bool) {
+			if checked {
+				chatPanel.SetRedactor(redactor)
+			} else {
+				chatPanel.SetRedactor(nil)
+			}
+		})
+
+		view := m.AddMenu("视图(&V)")
+		tabs := view.AddSubmenu("切换到(&T)")
+		for _, name := range app.Tabs() { // 菜单在 Run 中构建，此时所有 Tab 均已注册
+			name := name
+			tabs.AddItem(name, "", func() { app.SwitchTab(name) })
+		}
+		view.AddSeparator()
+		view.AddItem("隐藏窗口(&H)", "", app.HideWindow)
+
+		help := m.AddMenu("帮助(&H)")
+		help.AddItem("快捷键列表(&K)", "", app.ShowShortcuts)
+		help.AddItem("关于(&A)", "", func() { app.SwitchTab("关于") })
+	})
+
 	// 注册托盘菜单
 	app.RegisterTray(func(t *sdk.TrayProxy) {
+		// 图标随 AI 回复切换为忙碌动画、出错或未读角标，这里只调整提示文字
+		t.SetStateTooltip(sdk.TrayUnread, "{n} 条新回复")
+		// 左键单击默认切换窗口，右键显示菜单
 		t.AddMenuItem("显示/隐藏", "切换窗口", func() {
 			app.ToggleWindow()
 		})
-		t.AddSeparator()
-		t.AddMenuItem("主页", "切换到主页", func() {
-			app.SwitchTab("主页")
-			app.ShowWindow()
-		})
-		t.AddMenuItem("截图", "切换到截图", func() {
-			app.SwitchTab("截图")
-			app.ShowWindow()
+		t.AddMenuItem("标记为已读", "清除未读角标", func() {
+			t.SetBadge(0)
+			t.SetState(sdk.TrayIdle)
 		})
-		t.AddMenuItem("关于", "切换到关于", func() {
-			app.SwitchTab("关于")
-			app.ShowWindow()
+		t.AddSeparator()
+
+		// Tab 列表子菜单，按当前 Tab 勾选，切换 Tab 后重建
+		tabs := t.AddSubmenu("切换到", "切换 Tab")
+		updateTabs := func(event.Event) {
+			tabs.Rebuild(func(m *sdk.TrayMenuItem) {
+				for _, name := range app.Tabs() {
+					name := name
+					m.AddRadio("tab", name, "切换到"+name, name == app.ActiveTab(), func() {
+						app.SwitchTab(name)
+						app.ShowWindow()
+					})
+				}
+			})
+		}
+		updateTabs(event.Event{})
+		app.OnEvent(event.AppStart, updateTabs)
+		app.OnEvent(event.TabSwitch, updateTabs)
+
+		t.AddMenuItem("导出对话", "导出对话为 Markdown", func() {
+			if filename, err := chatPanel.ExportToFile("", sdk.ExportMarkdown); err != nil {
+				log.Printf("导出失败: %v", err)
+			} else {
+				log.Printf("对话已导出: %s", filename)
+			}
 		})
 		t.AddSeparator()
 		t.AddMenuItem("退出", "退出程序", func() {
@@ -198,9 +453,17 @@ func main() {
 		})
 	})
 
+	// 全局快捷键：显示/隐藏窗口
+	if err := app.RegisterHotkey("Ctrl+Shift+Space", app.ToggleWindow); err != nil {
+		log.Printf("注册快捷键失败: %v", err)
+	}
+
 	// 事件监听
 	app.OnEvent(event.AppStart, func(e event.Event) {
 		log.Println("应用已启动")
+		for _, issue := range app.Audit() {
+			log.Printf("无障碍检查: %s", issue)
+		}
 	})
 	app.OnEvent(event.AppExit, func(e event.Event) {
 		log.Println("应用已退出")
@@ -214,6 +477,26 @@ func main() {
 	app.OnEvent(event.WindowHide, func(e event.Event) {
 		log.Println("窗口已隐藏")
 	})
+	app.OnEvent(event.HotkeyPressed, func(e event.Event) {
+		log.Printf("快捷键: %v", e.Data)
+	})
+	app.OnEvent(event.MenuClicked, func(e event.Event) {
+		log.Printf("菜单: %v", e.Data)
+	})
+	app.OnEvent(event.TrayReady, func(e event.Event) {
+		log.Println("托盘已就绪")
+	})
+	app.OnEvent(event.TrayClicked, func(e event.Event) {
+		log.Printf("托盘图标点击: %v", e.Data)
+	})
+	app.OnEvent(event.TrayMenuClicked, func(e event.Event) {
+		log.Printf("托盘菜单: %v", e.Data)
+	})
+	app.OnEvent(event.ToolApproval, func(e event.Event) {
+		if ev, ok := e.Data.(sdk.ToolApprovalEvent); ok {
+			log.Printf("工具调用审批: %s -> %s", ev.ToolName, ev.Decision)
+		}
+	})
 
 	// 运行
 	if err := app.Run(); err != nil {
@@ -0,0 +1,169 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Modifiers 快捷键修饰键，取值与 RegisterHotKey 的 MOD_* 一致
+type Modifiers uint32
+
+const (
+	ModAlt   Modifiers = 1 << iota // MOD_ALT
+	ModCtrl                        // MOD_CONTROL
+	ModShift                       // MOD_SHIFT
+	ModWin                         // MOD_WIN
+)
+
+// modifierNames 修饰键名称，按规范输出顺序排列
+var modifierNames = []struct {
+	mod   Modifiers
+	names []string
+}{
+	{ModCtrl, []string{"Ctrl", "Control"}},
+	{ModAlt, []string{"Alt"}},
+	{ModShift, []string{"Shift"}},
+	{ModWin, []string{"Win", "Super", "Meta"}},
+}
+
+// keyNames 非字母数字按键的名称与虚拟键码，同一键码的第一个名称为规范名称
+var keyNames = []struct {
+	name string
+	key  int
+}{
+	{"Space", 0x20},
+	{"Enter", 0x0D}, {"Return", 0x0D},
+	{"Tab", 0x09},
+	{"Esc", 0x1B}, {"Escape", 0x1B},
+	{"Backspace", 0x08},
+	{"Insert", 0x2D}, {"Ins", 0x2D},
+	{"Delete", 0x2E}, {"Del", 0x2E},
+	{"Home", 0x24},
+	{"End", 0x23},
+	{"PageUp", 0x21}, {"PgUp", 0x21},
+	{"PageDown", 0x22}, {"PgDn", 0x22},
+	{"Left", 0x25},
+	{"Up", 0x26},
+	{"Right", 0x27},
+	{"Down", 0x28},
+	{"PrintScreen", 0x2C}, {"PrtSc", 0x2C}, {"Print", 0x2C},
+	{"Pause", 0x13},
+	{"Plus", 0xBB}, {"=", 0xBB},
+	{"Minus", 0xBD}, {"-", 0xBD},
+	{"Comma", 0xBC}, {",", 0xBC},
+	{"Period", 0xBE}, {".", 0xBE},
+	{"/", 0xBF}, {"`", 0xC0}, {";", 0xBA}, {"'", 0xDE},
+	{"[", 0xDB}, {"]", 0xDD}, {"\\", 0xDC},
+}
+
+// Accelerator 一个组合键，如 Ctrl+Shift+S
+type Accelerator struct {
+	Mods Modifiers
+	Key  int // 虚拟键码
+}
+
+// ParseAccelerator 解析组合键字符串，如 "Ctrl+Shift+S"、"Alt+F4"、"Win+PrintScreen"
+//
+// 名称不区分大小写，修饰键顺序任意，但必须恰好包含一个非修饰键。
+func ParseAccelerator(s string) (Accelerator, error) {
+	var acc Accelerator
+	parts := strings.Split(strings.TrimSpace(s), "+")
+	// "Ctrl++" 中最后的 "+" 表示加号键
+	if n := len(parts); n >= 2 && parts[n-1] == "" && parts[n-2] == "" {
+		parts = append(parts[:n-2], "Plus")
+	}
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return Accelerator{}, fmt.Errorf("无效的快捷键 %q", s)
+		}
+		if mod, ok := lookupModifier(part); ok {
+			if acc.Mods&mod != 0 {
+				return Accelerator{}, fmt.Errorf("快捷键 %q 中修饰键 %s 重复", s, part)
+			}
+			acc.Mods |= mod
+			continue
+		}
+		key, ok := lookupKey(part)
+		if !ok {
+			return Accelerator{}, fmt.Errorf("快捷键 %q 中的按键 %q 无法识别", s, part)
+		}
+		if acc.Key != 0 {
+			return Accelerator{}, fmt.Errorf("快捷键 %q 只能包含一个非修饰键", s)
+		}
+		acc.Key = key
+	}
+	if acc.Key == 0 {
+		return Accelerator{}, fmt.Errorf("快捷键 %q 缺少非修饰键", s)
+	}
+	return acc, nil
+}
+
+// MustParseAccelerator 解析组合键，失败时 panic，用于常量定义
+func MustParseAccelerator(s string) Accelerator {
+	acc, err := ParseAccelerator(s)
+	if err != nil {
+		panic(err)
+	}
+	return acc
+}
+
+// String 规范形式，如 "Ctrl+Shift+S"
+func (a Accelerator) String() string {
+	var parts []string
+	for _, m := range modifierNames {
+		if a.Mods&m.mod != 0 {
+			parts = append(parts, m.names[0])
+		}
+	}
+	return strings.Join(append(parts, keyName(a.Key)), "+")
+}
+
+func lookupModifier(name string) (Modifiers, bool) {
+	for _, m := range modifierNames {
+		for _, n := range m.names {
+			if strings.EqualFold(n, name) {
+				return m.mod, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// lookupKey 字母、数字、F1~F24、小键盘 Num0~Num9 与 keyNames 中的按键
+func lookupKey(name string) (int, bool) {
+	upper := strings.ToUpper(name)
+	if len(upper) == 1 && (upper[0] >= 'A' && upper[0] <= 'Z' || upper[0] >= '0' && upper[0] <= '9') {
+		return int(upper[0]), true
+	}
+	var n int
+	if _, err := fmt.Sscanf(upper, "F%d", &n); err == nil && fmt.Sprintf("F%d", n) == upper && n >= 1 && n <= 24 {
+		return 0x70 + n - 1, true
+	}
+	if _, err := fmt.Sscanf(upper, "NUM%d", &n); err == nil && fmt.Sprintf("NUM%d", n) == upper && n >= 0 && n <= 9 {
+		return 0x60 + n, true
+	}
+	for _, k := range keyNames {
+		if strings.EqualFold(k.name, name) {
+			return k.key, true
+		}
+	}
+	return 0, false
+}
+
+func keyName(key int) string {
+	switch {
+	case key >= 'A' && key <= 'Z', key >= '0' && key <= '9':
+		return string(rune(key))
+	case key >= 0x70 && key <= 0x87:
+		return fmt.Sprintf("F%d", key-0x70+1)
+	case key >= 0x60 && key <= 0x69:
+		return fmt.Sprintf("Num%d", key-0x60)
+	}
+	for _, k := range keyNames {
+		if k.key == key {
+			return k.name
+		}
+	}
+	return fmt.Sprintf("0x%02X", key)
+}
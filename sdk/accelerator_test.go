@@ -0,0 +1,111 @@
+package sdk
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAccelerator(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Accelerator
+		str   string
+	}{
+		{"加号键", "Ctrl++", Accelerator{Mods: ModCtrl, Key: 0xBB}, "Ctrl+Plus"},
+		{"修饰键顺序与大小写", "shift+CTRL+s", Accelerator{Mods: ModCtrl | ModShift, Key: 'S'}, "Ctrl+Shift+S"},
+		{"最大功能键", "Alt+F24", Accelerator{Mods: ModAlt, Key: 0x87}, "Alt+F24"},
+		{"小键盘", "Win+Num9", Accelerator{Mods: ModWin, Key: 0x69}, "Win+Num9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAccelerator(tt.input)
+			if err != nil {
+				t.Fatalf("ParseAccelerator(%q) 出错: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAccelerator(%q) = %+v，期望 %+v", tt.input, got, tt.want)
+			}
+			if got.String() != tt.str {
+				t.Errorf("String() = %q，期望 %q", got.String(), tt.str)
+			}
+		})
+	}
+}
+
+func TestParseAcceleratorInvalid(t *testing.T) {
+	for _, input := range []string{
+		"Ctrl+Ctrl+S",    // 修饰键重复
+		"Ctrl+Control+S", // 同一修饰键的别名
+		"Ctrl+F25",       // 超出 F1~F24
+		"Ctrl+F0",
+		"Ctrl+Num10", // 超出 Num0~Num9
+		"Ctrl+",
+		"Ctrl+Shift",
+		"Ctrl+A+B",
+	} {
+		if acc, err := ParseAccelerator(input); err == nil {
+			t.Errorf("ParseAccelerator(%q) = %v，期望出错", input, acc)
+		}
+	}
+}
+
+func TestHotkeysWithFakeBackend(t *testing.T) {
+	backend := NewFakeHotkeys("Ctrl+Alt+Del")
+	h := NewHotkeys(backend, nil)
+	defer h.Close()
+
+	pressed := 0
+	if err := h.Register("Ctrl++", func() { pressed++ }); err != nil {
+		t.Fatalf("注册 Ctrl++ 出错: %v", err)
+	}
+	// 同一组合键的不同写法对应同一个快捷键
+	if !backend.Press("ctrl+plus") || pressed != 1 {
+		t.Fatalf("按下 Ctrl+Plus 后 pressed = %d，期望 1", pressed)
+	}
+	if err := h.Register("Control+=", func() {}); !errors.Is(err, ErrHotkeyConflict) {
+		t.Errorf("重复注册 Ctrl+Plus 的错误 = %v，期望 ErrHotkeyConflict", err)
+	}
+	if err := h.Register("Ctrl+Alt+Delete", func() {}); !errors.Is(err, ErrHotkeyConflict) {
+		t.Errorf("注册被占用的组合键的错误 = %v，期望 ErrHotkeyConflict", err)
+	}
+
+	// 无法解析的组合键既不能注册，也不会触发
+	for _, input := range []string{"Ctrl+F25", "Ctrl+Num10", "Shift+Shift+A"} {
+		if err := h.Register(input, func() { t.Errorf("%s 不应触发", input) }); err == nil {
+			t.Errorf("注册 %q 应出错", input)
+		}
+		if backend.Press(input) {
+			t.Errorf("按下 %q 不应有快捷键响应", input)
+		}
+	}
+	if got := h.Registered(); len(got) != 1 || got[0].String() != "Ctrl+Plus" {
+		t.Errorf("Registered() = %v，期望只有 Ctrl+Plus", got)
+	}
+
+	if err := h.Unregister("Ctrl++"); err != nil {
+		t.Fatalf("注销出错: %v", err)
+	}
+	if backend.Press("Ctrl++") {
+		t.Error("注销后按下不应有快捷键响应")
+	}
+}
+
+func TestHotkeysDispatch(t *testing.T) {
+	backend := NewFakeHotkeys()
+	h := NewHotkeys(backend, nil)
+	defer h.Close()
+
+	var dispatched, pressed bool
+	h.dispatch = func(fn func()) {
+		dispatched = true
+		fn()
+	}
+	if err := h.Register("Ctrl+Shift+S", func() { pressed = true }); err != nil {
+		t.Fatal(err)
+	}
+	backend.Press("Ctrl+Shift+S")
+	if !dispatched || !pressed {
+		t.Errorf("dispatched=%v pressed=%v，处理函数应经 dispatch 执行", dispatched, pressed)
+	}
+}
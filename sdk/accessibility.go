@@ -0,0 +1,252 @@
+package sdk
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/gonutz/wui/v2"
+)
+
+// AccessibleRole 控件的无障碍角色
+type AccessibleRole string
+
+const (
+	RoleDefault     AccessibleRole = "" // 按控件类型推断
+	RoleButton      AccessibleRole = "button"
+	RoleCheckBox    AccessibleRole = "checkbox"
+	RoleRadio       AccessibleRole = "radio"
+	RoleComboBox    AccessibleRole = "combobox"
+	RoleTextBox     AccessibleRole = "textbox"
+	RoleSlider      AccessibleRole = "slider"
+	RoleList        AccessibleRole = "list"
+	RoleTable       AccessibleRole = "table"
+	RoleProgressBar AccessibleRole = "progressbar"
+	RoleImage       AccessibleRole = "image"
+	RoleLabel       AccessibleRole = "label"
+	RoleGroup       AccessibleRole = "group"
+)
+
+// defaultMinTargetSize 主题未设置 MinTargetSize 时的最小点击尺寸（WCAG 2.2 目标尺寸最低要求）
+const defaultMinTargetSize = 24
+
+// Accessibility 控件的无障碍信息
+type Accessibility struct {
+	Name        string
+	Description string
+	Role        AccessibleRole
+	Label       *wui.Label // 关联的标签，Name 为空时以标签文字作为名称
+}
+
+// SetAccessible 设置控件的无障碍名称与描述，读屏软件会朗读它们
+func (t *TabContext) SetAccessible(c wui.Control, name, description string) {
+	a := t.accessibility(c)
+	a.Name, a.Description = name, description
+	t.app.applyAccessibility(c, *a)
+}
+
+// SetAccessibleRole 覆盖按控件类型推断的无障碍角色
+func (t *TabContext) SetAccessibleRole(c wui.Control, role AccessibleRole) {
+	a := t.accessibility(c)
+	a.Role = role
+	t.app.applyAccessibility(c, *a)
+}
+
+// LabelFor 将标签关联到输入控件，控件未设置名称时以标签文字作为名称
+func (t *TabContext) LabelFor(label *wui.Label, c wui.Control) {
+	a := t.accessibility(c)
+	a.Label = label
+	t.app.applyAccessibility(c, *a)
+}
+
+// AddLabeledEditLine 添加标签与单行输入框并关联二者，标签宽 labelWidth，位于输入框左侧
+func (t *TabContext) AddLabeledEditLine(text string, x, y, labelWidth, w, h int) (*wui.Label, *wui.EditLine) {
+	label := t.AddLabel(text, x, y+3, labelWidth, h)
+	edit := t.AddEditLine(x+labelWidth+10, y, w, h)
+	t.LabelFor(label, edit)
+	return label, edit
+}
+
+// Accessibility 控件实际生效的无障碍信息：名称依次取自设置的名称、关联标签与控件文字，角色未设置时按类型推断
+func (t *TabContext) Accessibility(c wui.Control) Accessibility {
+	var a Accessibility
+	if stored, ok := t.access[c]; ok {
+		a = *stored
+	}
+	if a.Name == "" && a.Label != nil {
+		a.Name = a.Label.Text()
+	}
+	if a.Name == "" {
+		a.Name = controlText(c)
+	}
+	if a.Role == RoleDefault {
+		a.Role = inferRole(c)
+	}
+	return a
+}
+
+// accessibility 控件的无障碍设置，不存在时创建
+func (t *TabContext) accessibility(c wui.Control) *Accessibility {
+	if t.access == nil {
+		t.access = make(map[wui.Control]*Accessibility)
+	}
+	a, ok := t.access[c]
+	if !ok {
+		a = &Accessibility{}
+		t.access[c] = a
+	}
+	return a
+}
+
+// controlText 以文字作为名称的控件（按钮、复选框等）的文字
+func controlText(c wui.Control) string {
+	switch c := c.(type) {
+	case *wui.Button:
+		return c.Text()
+	case *wui.CheckBox:
+		return c.Text()
+	case *wui.RadioButton:
+		return c.Text()
+	case *wui.Label:
+		return c.Text()
+	}
+	return ""
+}
+
+// inferRole 按控件类型推断角色
+func inferRole(c wui.Control) AccessibleRole {
+	switch c.(type) {
+	case *wui.Button:
+		return RoleButton
+	case *wui.CheckBox:
+		return RoleCheckBox
+	case *wui.RadioButton:
+		return RoleRadio
+	case *wui.ComboBox:
+		return RoleComboBox
+	case *wui.EditLine, *wui.TextEdit, *wui.IntUpDown, *wui.FloatUpDown:
+		return RoleTextBox
+	case *wui.Slider:
+		return RoleSlider
+	case *wui.StringList:
+		return RoleList
+	case *wui.StringTable:
+		return RoleTable
+	case *wui.ProgressBar:
+		return RoleProgressBar
+	case *wui.PaintBox:
+		return RoleImage
+	case *wui.Label:
+		return RoleLabel
+	case *wui.Panel:
+		return RoleGroup
+	}
+	return RoleDefault
+}
+
+// AuditKind 无障碍检查发现的问题类型
+type AuditKind int
+
+const (
+	AuditUnlabeled   AuditKind = iota // 可交互控件没有名称
+	AuditSmallTarget                  // 可交互控件小于最小点击尺寸
+)
+
+// String 返回问题类型名称
+func (k AuditKind) String() string {
+	if k == AuditSmallTarget {
+		return "small-target"
+	}
+	return "unlabeled"
+}
+
+// AuditIssue 无障碍检查发现的一个问题
+type AuditIssue struct {
+	Tab     string
+	Control wui.Control
+	Kind    AuditKind
+	Message string
+}
+
+// String 返回问题描述
+func (i AuditIssue) String() string {
+	return fmt.Sprintf("[%s] %s", i.Tab, i.Message)
+}
+
+// Audit 检查本 Tab 中的控件：没有名称的输入控件与小于最小点击尺寸的可交互控件；隐藏的控件不检查
+func (t *TabContext) Audit() []AuditIssue {
+	minSize := t.app.Theme().MinTargetSize
+	if minSize <= 0 {
+		minSize = defaultMinTargetSize
+	}
+	var issues []AuditIssue
+	for _, c := range t.focusables() {
+		if !wui.Visible(c) {
+			continue
+		}
+		a := t.Accessibility(c)
+		_, _, w, h := c.Bounds()
+		desc := describeControl(a, w, h)
+		if a.Name == "" {
+			issues = append(issues, AuditIssue{Tab: t.name, Control: c, Kind: AuditUnlabeled,
+				Message: fmt.Sprintf("%s 没有无障碍名称，请用 LabelFor 关联标签或用 SetAccessible 设置名称", desc)})
+		}
+		if w < minSize || h < minSize {
+			issues = append(issues, AuditIssue{Tab: t.name, Control: c, Kind: AuditSmallTarget,
+				Message: fmt.Sprintf("%s 尺寸 %dx%d 小于最小点击尺寸 %dx%d", desc, w, h, minSize, minSize)})
+		}
+	}
+	return issues
+}
+
+// Audit 检查所有 Tab
+func (app *App) Audit() []AuditIssue {
+	var issues []AuditIssue
+	for _, name := range app.tabOrder {
+		if t, ok := app.tabs[name]; ok {
+			issues = append(issues, t.Audit()...)
+		}
+	}
+	return issues
+}
+
+// describeControl 问题描述中的控件说明，如 `button "发送"`
+func describeControl(a Accessibility, w, h int) string {
+	if a.Name != "" {
+		return fmt.Sprintf("%s %q", a.Role, a.Name)
+	}
+	return fmt.Sprintf("%s (%dx%d)", a.Role, w, h)
+}
+
+// applyAccessibility 窗口已显示时立即把无障碍信息提供给读屏软件，否则等窗口显示时统一设置
+func (app *App) applyAccessibility(c wui.Control, a Accessibility) {
+	if c.Handle() == 0 {
+		return
+	}
+	if app.accProps == nil && app.accErr == nil {
+		if app.accProps, app.accErr = newAccPropServices(); app.accErr != nil {
+			log.Printf("Accessibility services unavailable: %v", app.accErr)
+		}
+	}
+	if app.accProps == nil {
+		return
+	}
+	if a.Name == "" && a.Label != nil {
+		a.Name = a.Label.Text()
+	}
+	if err := app.accProps.apply(c.Handle(), a); err != nil {
+		log.Printf("Set accessibility properties failed: %v", err)
+	}
+}
+
+// applyAllAccessibility 窗口显示后设置所有已登记的无障碍信息
+func (app *App) applyAllAccessibility() {
+	for _, name := range app.tabOrder {
+		t, ok := app.tabs[name]
+		if !ok {
+			continue
+		}
+		for c, a := range t.access {
+			app.applyAccessibility(c, *a)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package sdk
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	w32 "github.com/gonutz/w32/v2"
+)
+
+var procCoCreateInstance = syscall.NewLazyDLL("ole32.dll").NewProc("CoCreateInstance")
+
+// oleacc.h 中的 COM 标识
+var (
+	clsidAccPropServices = w32.GUID{Data1: 0xb5f8350b, Data2: 0x0548, Data3: 0x48b1, Data4: [8]byte{0xa6, 0xee, 0x88, 0xbd, 0x00, 0xb4, 0xa5, 0xe7}}
+	iidIAccPropServices  = w32.GUID{Data1: 0x6e26e776, Data2: 0x04f0, Data3: 0x495d, Data4: [8]byte{0x80, 0xe4, 0x33, 0x30, 0x35, 0x2e, 0x31, 0x69}}
+	propIDAccName        = w32.GUID{Data1: 0x608d3df8, Data2: 0x8128, Data3: 0x4aa7, Data4: [8]byte{0xa4, 0x28, 0xf5, 0x5e, 0x49, 0x26, 0x72, 0x91}}
+	propIDAccDescription = w32.GUID{Data1: 0x4d48dfe4, Data2: 0xbd3f, Data3: 0x491f, Data4: [8]byte{0xa6, 0x48, 0x49, 0x2d, 0x6f, 0x20, 0xc5, 0x88}}
+	propIDAccRole        = w32.GUID{Data1: 0xcb905ff2, Data2: 0x7bd1, Data3: 0x4c05, Data4: [8]byte{0xb3, 0xc8, 0xe6, 0xc2, 0x41, 0x36, 0x4d, 0x70}}
+)
+
+const (
+	clsctxInprocServer = 0x1
+	coinitApartment    = 0x2
+	objIDClient        = 0xFFFFFFFC // OBJID_CLIENT
+	childIDSelf        = 0
+	vtI4               = 3
+
+	// IAccPropServices 虚函数表下标（前三个为 IUnknown）
+	accPropSetHwndProp    = 6
+	accPropSetHwndPropStr = 7
+)
+
+// msaaRoles 角色对应的 MSAA ROLE_SYSTEM_* 值
+var msaaRoles = map[AccessibleRole]int32{
+	RoleButton:      0x2B,
+	RoleCheckBox:    0x2C,
+	RoleRadio:       0x2D,
+	RoleComboBox:    0x2E,
+	RoleTextBox:     0x2A,
+	RoleSlider:      0x33,
+	RoleList:        0x21,
+	RoleTable:       0x18,
+	RoleProgressBar: 0x30,
+	RoleImage:       0x28,
+	RoleLabel:       0x29,
+	RoleGroup:       0x14,
+}
+
+// accPropServices 通过 IAccPropServices 为控件窗口覆盖 MSAA 名称、描述与角色，必须在界面线程上使用
+type accPropServices struct {
+	obj unsafe.Pointer
+}
+
+func newAccPropServices() (*accPropServices, error) {
+	w32.CoInitializeEx(coinitApartment) // 已初始化时返回 S_FALSE 或 RPC_E_CHANGED_MODE，均可继续
+	var obj unsafe.Pointer
+	hr, _, _ := procCoCreateInstance.Call(
+		uintptr(unsafe.Pointer(&clsidAccPropServices)), 0, clsctxInprocServer,
+		uintptr(unsafe.Pointer(&iidIAccPropServices)), uintptr(unsafe.Pointer(&obj)))
+	if int32(hr) < 0 {
+		return nil, fmt.Errorf("创建无障碍属性服务失败: 0x%08X", uint32(hr))
+	}
+	return &accPropServices{obj: obj}, nil
+}
+
+// apply 设置名称、描述与显式指定的角色，空值不覆盖控件原有信息
+func (s *accPropServices) apply(hwnd uintptr, a Accessibility) error {
+	if a.Name != "" {
+		if err := s.setString(hwnd, &propIDAccName, a.Name); err != nil {
+			return err
+		}
+	}
+	if a.Description != "" {
+		if err := s.setString(hwnd, &propIDAccDescription, a.Description); err != nil {
+			return err
+		}
+	}
+	if role, ok := msaaRoles[a.Role]; ok {
+		return s.setInt(hwnd, &propIDAccRole, role)
+	}
+	return nil
+}
+
+func (s *accPropServices) setString(hwnd uintptr, prop *w32.GUID, value string) error {
+	str, err := syscall.UTF16PtrFromString(value)
+	if err != nil {
+		return err
+	}
+	args := append([]uintptr{uintptr(s.obj), hwnd, objIDClient, childIDSelf}, byValue(unsafe.Pointer(prop), unsafe.Sizeof(*prop))...)
+	args = append(args, uintptr(unsafe.Pointer(str)))
+	return s.call(accPropSetHwndPropStr, args)
+}
+
+func (s *accPropServices) setInt(hwnd uintptr, prop *w32.GUID, value int32) error {
+	// VARIANT：vt 与三个保留字段之后是 8 字节的值；64 位下另有 8 字节填充
+	var variant [3]uint64
+	*(*uint16)(unsafe.Pointer(&variant[0])) = vtI4
+	*(*int32)(unsafe.Pointer(&variant[1])) = value
+	size := unsafe.Sizeof(variant)
+	if runtime.GOARCH == "386" {
+		size = 16
+	}
+	args := append([]uintptr{uintptr(s.obj), hwnd, objIDClient, childIDSelf}, byValue(unsafe.Pointer(prop), unsafe.Sizeof(*prop))...)
+	args = append(args, byValue(unsafe.Pointer(&variant[0]), size)...)
+	return s.call(accPropSetHwndProp, args)
+}
+
+// call 调用虚函数表中第 index 个方法
+func (s *accPropServices) call(index int, args []uintptr) error {
+	vtbl := *(*unsafe.Pointer)(s.obj)
+	method := *(*uintptr)(unsafe.Add(vtbl, uintptr(index)*unsafe.Sizeof(uintptr(0))))
+	hr, _, _ := syscall.SyscallN(method, args...)
+	if int32(hr) < 0 {
+		return fmt.Errorf("设置无障碍属性失败: 0x%08X", uint32(hr))
+	}
+	return nil
+}
+
+// byValue 按调用约定展开按值传递的结构体参数：
+// amd64 上超过 8 字节的结构体传地址；arm64 上不超过 16 字节的拆入寄存器，更大的传地址；386 逐个双字压栈
+func byValue(p unsafe.Pointer, size uintptr) []uintptr {
+	word := unsafe.Sizeof(uintptr(0))
+	switch {
+	case runtime.GOARCH == "amd64" && size > 8, runtime.GOARCH == "arm64" && size > 16:
+		return []uintptr{uintptr(p)}
+	}
+	words := make([]uintptr, (size+word-1)/word)
+	for i := range words {
+		words[i] = *(*uintptr)(unsafe.Add(p, uintptr(i)*word))
+	}
+	return words
+}
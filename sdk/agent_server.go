@@ -0,0 +1,136 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	a2aserver "trpc.group/trpc-go/trpc-a2a-go/server"
+	"trpc.group/trpc-go/trpc-agent-go/server/a2a"
+	"trpc.group/trpc-go/trpc-agent-go/server/openai"
+)
+
+// 本地 Agent 服务默认配置
+const (
+	defaultAgentServerAddr = "127.0.0.1:8765"
+	a2aBasePath            = "/a2a"
+)
+
+// AgentServerConfig 本地 Agent 服务配置
+type AgentServerConfig struct {
+	Addr      string // 监听地址，仅允许回环地址，默认 127.0.0.1:8765
+	A2A       bool   // 启用 A2A 协议端点（/a2a）
+	OpenAI    bool   // 启用 OpenAI 兼容端点（/v1/chat/completions）
+	ModelName string // OpenAI 端点返回的模型名
+}
+
+// AgentServer 本地 Agent 服务，供本机其它 Agent 与脚本调用同一个助手
+type AgentServer struct {
+	httpServer *http.Server
+	listener   net.Listener
+	a2a        *a2aserver.A2AServer
+	openai     *openai.Server
+	closeOnce  sync.Once
+	closeErr   error
+}
+
+// StartServer 在本机回环地址上暴露 AIService 的 Agent
+func (a *AIService) StartServer(config AgentServerConfig) (*AgentServer, error) {
+	if !config.A2A && !config.OpenAI {
+		return nil, errors.New("至少需要启用 A2A 或 OpenAI 端点")
+	}
+
+	addr := config.Addr
+	if addr == "" {
+		addr = defaultAgentServerAddr
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("监听地址无效: %w", err)
+	}
+	if !isLoopbackHost(host) {
+		return nil, fmt.Errorf("Agent 服务只允许监听本机地址: %s", addr)
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("监听失败: %w", err)
+	}
+
+	s := &AgentServer{listener: listener}
+	mux := http.NewServeMux()
+
+	if config.A2A {
+		s.a2a, err = a2a.New(
+			a2a.WithAgent(a.agent, true),
+			a2a.WithHost("http://"+listener.Addr().String()+a2aBasePath),
+		)
+		if err != nil {
+			listener.Close()
+			return nil, fmt.Errorf("创建 A2A 服务失败: %w", err)
+		}
+		mux.Handle(a2aBasePath+"/", s.a2a.Handler())
+	}
+
+	if config.OpenAI {
+		opts := []openai.Option{openai.WithAgent(a.agent)}
+		if config.ModelName != "" {
+			opts = append(opts, openai.WithModelName(config.ModelName))
+		}
+		s.openai, err = openai.New(opts...)
+		if err != nil {
+			listener.Close()
+			return nil, fmt.Errorf("创建 OpenAI 兼容服务失败: %w", err)
+		}
+		mux.Handle(s.openai.BasePath()+"/", s.openai.Handler())
+	}
+
+	s.httpServer = &http.Server{Handler: mux}
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("Agent server error: %v", err)
+		}
+	}()
+
+	a.servers = append(a.servers, s)
+	return s, nil
+}
+
+// Addr 实际监听地址
+func (s *AgentServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// A2AURL A2A 端点地址（Agent Card 位于 /.well-known/agent-card.json）
+func (s *AgentServer) A2AURL() string {
+	if s.a2a == nil {
+		return ""
+	}
+	return "http://" + s.Addr() + a2aBasePath
+}
+
+// OpenAIURL OpenAI 兼容 chat completions 端点地址
+func (s *AgentServer) OpenAIURL() string {
+	if s.openai == nil {
+		return ""
+	}
+	return "http://" + s.Addr() + s.openai.Path()
+}
+
+// Close 关闭服务，可重复调用
+func (s *AgentServer) Close() error {
+	s.closeOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.closeErr = s.httpServer.Shutdown(ctx)
+		if s.openai != nil {
+			s.openai.Close()
+		}
+	})
+	return s.closeErr
+}
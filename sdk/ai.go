@@ -3,6 +3,8 @@ package sdk
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	"trpc.group/trpc-go/trpc-agent-go/agent"
 	"trpc.group/trpc-go/trpc-agent-go/agent/llmagent"
@@ -10,6 +12,7 @@ import (
 	"trpc.group/trpc-go/trpc-agent-go/model/openai"
 	"trpc.group/trpc-go/trpc-agent-go/runner"
 	"trpc.group/trpc-go/trpc-agent-go/session/inmemory"
+	"trpc.group/trpc-go/trpc-agent-go/tool"
 )
 
 // AIService 封装 AI 对话服务
@@ -18,6 +21,19 @@ type AIService struct {
 	agent  agent.Agent
 	ctx    context.Context
 	userID string
+
+	// MCP 工具
+	toolSets  []tool.ToolSet
+	mcpStatus []MCPServerStatus
+
+	// 本地 Agent 服务
+	servers []*AgentServer
+
+	// 敏感工具审批
+	approval *toolApproval
+
+	// runner 事件追踪
+	tracer *Tracer
 }
 
 // AIServiceConfig AI 服务配置
@@ -26,6 +42,12 @@ type AIServiceConfig struct {
 	BaseURL string
 	Model   string
 	UserID  string
+
+	// MCPServers 助手可使用的 MCP 工具服务器
+	MCPServers []MCPServerConfig
+
+	// SensitiveTools 调用前需要用户审批的工具名
+	SensitiveTools []string
 }
 
 // NewAIService 创建新的 AI 服务
@@ -43,6 +65,15 @@ func NewAIService(config AIServiceConfig) *AIService {
 		Temperature: floatPtr(0.7),
 	}
 
+	// 连接 MCP 服务器
+	toolSets, mcpStatus := connectMCPServers(config.MCPServers)
+
+	// 敏感工具审批
+	approval := newToolApproval(config.SensitiveTools)
+
+	// 事件追踪
+	tracer := NewTracer(0)
+
 	// 创建 LLM Agent
 	agentInstance := llmagent.New(
 		"ai-assistant",
@@ -52,6 +83,9 @@ func NewAIService(config AIServiceConfig) *AIService {
 You can help users with various tasks and answer their questions.
 Always respond in the same language the user writes in.`),
 		llmagent.WithDescription("GUI Application AI Assistant"),
+		llmagent.WithToolSets(toolSets),
+		llmagent.WithToolCallbacks(approval.callbacks()),
+		llmagent.WithModelCallbacks(tracer.modelCallbacks()),
 	)
 
 	// 创建 Session Service
@@ -70,10 +104,14 @@ Always respond in the same language the user writes in.`),
 	}
 
 	return &AIService{
-		runner: runnerInstance,
-		agent:  agentInstance,
-		ctx:    context.Background(),
-		userID: userID,
+		runner:    runnerInstance,
+		agent:     agentInstance,
+		ctx:       context.Background(),
+		userID:    userID,
+		toolSets:  toolSets,
+		mcpStatus: mcpStatus,
+		approval:  approval,
+		tracer:    tracer,
 	}
 }
 
@@ -81,20 +119,25 @@ Always respond in the same language the user writes in.`),
 func (a *AIService) Chat(message string) (string, error) {
 	sessionID := generateSessionID()
 	userMessage := model.NewUserMessage(message)
+	a.tracer.begin(sessionID, message)
 
 	// 运行 Runner
 	eventCh, err := a.runner.Run(a.ctx, a.userID, sessionID, userMessage)
 	if err != nil {
+		a.tracer.end(sessionID, err)
 		return "", fmt.Errorf("AI 调用失败: %w", err)
 	}
 
 	// 收集事件，获取最终回复
 	var finalContent string
 	for event := range eventCh {
+		a.tracer.record(sessionID, event)
 		if event.Error != nil {
 			// 关闭 runner
 			a.runner.Close()
-			return "", fmt.Errorf("AI 返回错误: %s", event.Error.Message)
+			err := fmt.Errorf("AI 返回错误: %s", event.Error.Message)
+			a.tracer.end(sessionID, err)
+			return "", err
 		}
 
 		// 检查是否是完成事件
@@ -109,6 +152,7 @@ func (a *AIService) Chat(message string) (string, error) {
 
 	// 关闭 runner
 	a.runner.Close()
+	a.tracer.end(sessionID, nil)
 
 	return finalContent, nil
 }
@@ -117,18 +161,23 @@ func (a *AIService) Chat(message string) (string, error) {
 func (a *AIService) ChatStream(message string, callback func(chunk string)) error {
 	sessionID := generateSessionID()
 	userMessage := model.NewUserMessage(message)
+	a.tracer.begin(sessionID, message)
 
 	// 运行 Runner
 	eventCh, err := a.runner.Run(a.ctx, a.userID, sessionID, userMessage)
 	if err != nil {
+		a.tracer.end(sessionID, err)
 		return fmt.Errorf("AI 调用失败: %w", err)
 	}
 
 	// 处理流式事件
 	for event := range eventCh {
+		a.tracer.record(sessionID, event)
 		if event.Error != nil {
 			a.runner.Close()
-			return fmt.Errorf("AI 返回错误: %s", event.Error.Message)
+			err := fmt.Errorf("AI 返回错误: %s", event.Error.Message)
+			a.tracer.end(sessionID, err)
+			return err
 		}
 
 		// 检查是否有新的内容块
@@ -148,12 +197,24 @@ func (a *AIService) ChatStream(message string, callback func(chunk string)) erro
 
 	// 关闭 runner
 	a.runner.Close()
+	a.tracer.end(sessionID, nil)
 
 	return nil
 }
 
+// MCPServers 获取 MCP 服务器连接状态及已发现的工具
+func (a *AIService) MCPServers() []MCPServerStatus {
+	return a.mcpStatus
+}
+
 // Close 关闭 AI 服务
 func (a *AIService) Close() error {
+	for _, s := range a.servers {
+		s.Close()
+	}
+	for _, ts := range a.toolSets {
+		ts.Close()
+	}
 	return a.runner.Close()
 }
 
@@ -166,7 +227,10 @@ func floatPtr(f float64) *float64 {
 	return &f
 }
 
-// generateSessionID 生成会话ID（简化版）
+// sessionSeq 会话 ID 序号，保证同一时刻生成的 ID 也不重复
+var sessionSeq atomic.Uint64
+
+// generateSessionID 为每次对话生成唯一的会话ID，并发的对话与追踪轮次互不干扰
 func generateSessionID() string {
-	return "session-" + fmt.Sprintf("%d", len("default-user"))
+	return fmt.Sprintf("session-%d-%d", time.Now().UnixNano(), sessionSeq.Add(1))
 }
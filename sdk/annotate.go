@@ -0,0 +1,354 @@
+package sdk
+
+import (
+	"image"
+	"image/color"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gonutz/wui/v2"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+
+	"github.com/package-register/gui/imaging"
+)
+
+// 标注编辑器布局
+const (
+	annotateToolbarHeight = 30
+	annotateButtonWidth   = 48
+	annotateSpacing       = 4
+	annotateFontSize      = 18
+)
+
+// annotateTools 工具栏上的工具按钮
+var annotateTools = []struct {
+	name string
+	tool imaging.Tool
+}{
+	{"箭头", imaging.ToolArrow},
+	{"矩形", imaging.ToolRect},
+	{"画笔", imaging.ToolPen},
+	{"文字", imaging.ToolText},
+	{"高亮", imaging.ToolHighlight},
+	{"马赛克", imaging.ToolPixelate},
+	{"模糊", imaging.ToolBlur},
+}
+
+// annotateColors 可选的标注颜色
+var annotateColors = []struct {
+	name  string
+	color color.NRGBA
+}{
+	{"红色", color.NRGBA{R: 230, G: 30, B: 30, A: 255}},
+	{"绿色", color.NRGBA{R: 30, G: 170, B: 60, A: 255}},
+	{"蓝色", color.NRGBA{R: 0, G: 120, B: 215, A: 255}},
+	{"黄色", color.NRGBA{R: 255, G: 210, B: 0, A: 255}},
+	{"黑色", color.NRGBA{A: 255}},
+	{"白色", color.NRGBA{R: 255, G: 255, B: 255, A: 255}},
+}
+
+// AnnotationEditor 截图标注编辑器，在 ImageDisplay 上绘制箭头、矩形、画笔、文字、高亮与打码
+type AnnotationEditor struct {
+	display   *ImageDisplay
+	toolBtns  []*wui.Button
+	textInput *wui.EditLine
+	undoBtn   *wui.Button
+	redoBtn   *wui.Button
+
+	doc      *imaging.Document
+	tool     imaging.Tool
+	color    color.NRGBA
+	width    int
+	current  *imaging.Annotation // 正在绘制的标注
+	onChange []func(img image.Image)
+}
+
+// AddAnnotationEditor 添加标注编辑器：上方为工具栏，下方为图片显示区域
+func (t *TabContext) AddAnnotationEditor(x, y, w, h int) *AnnotationEditor {
+	e := &AnnotationEditor{
+		doc:   imaging.NewDocument(nil),
+		tool:  imaging.ToolArrow,
+		color: annotateColors[0].color,
+		width: imaging.DefaultWidth,
+	}
+	e.doc.SetFace(annotationFace())
+
+	bx := x
+	addButton := func(text string, onClick func()) *wui.Button {
+		btn := t.AddButton(text, bx, y, annotateButtonWidth, annotateToolbarHeight, onClick)
+		bx += annotateButtonWidth + annotateSpacing
+		return btn
+	}
+
+	for _, item := range annotateTools {
+		tool := item.tool
+		e.toolBtns = append(e.toolBtns, addButton(item.name, func() { e.SetTool(tool) }))
+	}
+
+	colorBox := wui.NewComboBox()
+	colorBox.SetBounds(bx, y+3, 64, annotateToolbarHeight)
+	for _, c := range annotateColors {
+		colorBox.AddItem(c.name)
+	}
+	colorBox.SetSelectedIndex(0)
+	colorBox.SetOnChange(func(i int) {
+		if i >= 0 && i < len(annotateColors) {
+			e.SetColor(annotateColors[i].color)
+		}
+	})
+	t.panel.Add(colorBox)
+	t.SetAccessible(colorBox, "标注颜色", "")
+	bx += 64 + annotateSpacing
+
+	e.textInput = wui.NewEditLine()
+	e.textInput.SetBounds(bx, y+3, 110, annotateToolbarHeight-6)
+	e.textInput.SetText("文字标注")
+	t.panel.Add(e.textInput)
+	t.SetAccessible(e.textInput, "标注文字", "文字工具写入的内容")
+	bx += 110 + annotateSpacing
+
+	e.undoBtn = addButton("撤销", func() { e.Undo() })
+	e.redoBtn = addButton("重做", func() { e.Redo() })
+	addButton("清除", func() { e.Clear() })
+
+	imgY := y + annotateToolbarHeight + annotateSpacing
+	e.display = t.AddImage(x, imgY, w, h-(imgY-y))
+	e.display.editor = e
+	e.updateToolbar()
+	return e
+}
+
+// Display 编辑器使用的图片显示组件
+func (e *AnnotationEditor) Display() *ImageDisplay {
+	return e.display
+}
+
+// Document 标注文档
+func (e *AnnotationEditor) Document() *imaging.Document {
+	return e.doc
+}
+
+// SetImage 设置待标注的图片，清空已有标注
+func (e *AnnotationEditor) SetImage(img image.Image) {
+	e.display.SetImage(img)
+}
+
+// Image 渲染了标注的图片
+func (e *AnnotationEditor) Image() image.Image {
+	return e.display.GetImage()
+}
+
+// OnChange 订阅标注变化，参数为渲染后的图片
+func (e *AnnotationEditor) OnChange(handler func(img image.Image)) {
+	e.onChange = append(e.onChange, handler)
+}
+
+// SetTool 选择标注工具
+func (e *AnnotationEditor) SetTool(tool imaging.Tool) {
+	e.tool = tool
+	e.current = nil
+	e.updateToolbar()
+	if tool == imaging.ToolText {
+		e.textInput.Focus()
+	}
+}
+
+// Tool 当前标注工具
+func (e *AnnotationEditor) Tool() imaging.Tool {
+	return e.tool
+}
+
+// SetColor 设置标注颜色
+func (e *AnnotationEditor) SetColor(c color.NRGBA) {
+	e.color = c
+}
+
+// SetWidth 设置线宽
+func (e *AnnotationEditor) SetWidth(width int) {
+	e.width = width
+}
+
+// Undo 撤销
+func (e *AnnotationEditor) Undo() {
+	if e.doc.Undo() {
+		e.refresh()
+	}
+}
+
+// Redo 重做
+func (e *AnnotationEditor) Redo() {
+	if e.doc.Redo() {
+		e.refresh()
+	}
+}
+
+// Clear 清除所有标注（可撤销）
+func (e *AnnotationEditor) Clear() {
+	e.doc.Clear()
+	e.refresh()
+}
+
+// reset 更换底图
+func (e *AnnotationEditor) reset(base image.Image) {
+	e.doc = imaging.NewDocument(base)
+	e.doc.SetFace(annotationFace())
+	e.current = nil
+	e.updateToolbar()
+}
+
+// refresh 重新渲染标注并显示
+func (e *AnnotationEditor) refresh() {
+	if e.doc.Base() == nil {
+		return
+	}
+	rendered := e.doc.Render()
+	e.display.setImage(rendered)
+	e.updateToolbar()
+	for _, h := range e.onChange {
+		h(rendered)
+	}
+}
+
+// updateToolbar 更新工具栏状态，当前工具用方括号标出
+func (e *AnnotationEditor) updateToolbar() {
+	for i, btn := range e.toolBtns {
+		name := annotateTools[i].name
+		if annotateTools[i].tool == e.tool {
+			name = "[" + name + "]"
+		}
+		btn.SetText(name)
+	}
+	e.undoBtn.SetEnabled(e.doc.CanUndo())
+	e.redoBtn.SetEnabled(e.doc.CanRedo())
+}
+
+// toImage 显示区域坐标转换为图片坐标
+func (e *AnnotationEditor) toImage(x, y int) image.Point {
+	p, _ := e.display.view.ToImage(x, y)
+	return p.Add(e.display.image.Bounds().Min)
+}
+
+func (e *AnnotationEditor) mouseDown(button wui.MouseButton, x, y int) {
+	if e.display.image == nil {
+		return
+	}
+	if button == wui.MouseButtonRight {
+		// 右键放弃正在绘制的标注
+		e.current = nil
+		e.display.paintBox.Paint()
+		return
+	}
+	if button != wui.MouseButtonLeft {
+		return
+	}
+
+	p := e.toImage(x, y)
+	if e.tool == imaging.ToolText {
+		text := e.textInput.Text()
+		if text == "" {
+			e.textInput.Focus()
+			return
+		}
+		e.doc.Add(imaging.Annotation{Tool: imaging.ToolText, Points: []image.Point{p}, Color: e.color, Text: text})
+		e.refresh()
+		return
+	}
+	e.current = &imaging.Annotation{Tool: e.tool, Points: []image.Point{p, p}, Color: e.color, Width: e.width}
+}
+
+func (e *AnnotationEditor) mouseMove(x, y int) {
+	if e.current == nil {
+		return
+	}
+	p := e.toImage(x, y)
+	if e.current.Tool == imaging.ToolPen {
+		if p != e.current.Points[len(e.current.Points)-1] {
+			e.current.Points = append(e.current.Points, p)
+		}
+	} else {
+		e.current.Points[1] = p
+	}
+	e.display.paintBox.Paint()
+}
+
+func (e *AnnotationEditor) mouseUp(button wui.MouseButton, x, y int) {
+	if button != wui.MouseButtonLeft || e.current == nil {
+		return
+	}
+	e.mouseMove(x, y)
+	a := *e.current
+	e.current = nil
+
+	// 零长度的箭头和零面积的矩形类标注直接丢弃
+	switch a.Tool {
+	case imaging.ToolArrow:
+		if a.Points[0] == a.Points[1] {
+			e.display.paintBox.Paint()
+			return
+		}
+	case imaging.ToolRect, imaging.ToolHighlight, imaging.ToolPixelate, imaging.ToolBlur:
+		if a.Rect().Empty() {
+			e.display.paintBox.Paint()
+			return
+		}
+	}
+	e.doc.Add(a)
+	e.refresh()
+}
+
+// paintPreview 绘制正在拖拽中的标注轮廓，松开鼠标后才真正渲染
+func (e *AnnotationEditor) paintPreview(canvas *wui.Canvas) {
+	if e.current == nil {
+		return
+	}
+	base := e.display.image.Bounds().Min
+	pts := make([]wui.Point, len(e.current.Points))
+	for i, p := range e.current.Points {
+		v := e.display.view.ToView(p.Sub(base))
+		pts[i] = wui.Point{X: int32(v.X), Y: int32(v.Y)}
+	}
+	c := wui.RGB(e.color.R, e.color.G, e.color.B)
+
+	switch e.current.Tool {
+	case imaging.ToolArrow, imaging.ToolPen:
+		canvas.Polyline(pts, c)
+	default:
+		r := image.Rect(int(pts[0].X), int(pts[0].Y), int(pts[1].X), int(pts[1].Y))
+		canvas.DrawRect(r.Min.X, r.Min.Y, r.Dx(), r.Dy(), c)
+	}
+}
+
+var (
+	faceOnce sync.Once
+	face     font.Face
+)
+
+// annotationFace 文字标注使用的字体：优先加载系统中文字体，失败时返回 nil（使用内置等宽字体）
+func annotationFace() font.Face {
+	faceOnce.Do(func() {
+		dir := filepath.Join(os.Getenv("WINDIR"), "Fonts")
+		for _, name := range []string{"msyh.ttc", "simhei.ttf", "simsun.ttc"} {
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				continue
+			}
+			coll, err := opentype.ParseCollection(data)
+			if err != nil || coll.NumFonts() == 0 {
+				continue
+			}
+			f, err := coll.Font(0)
+			if err != nil {
+				continue
+			}
+			face, err = opentype.NewFace(f, &opentype.FaceOptions{Size: annotateFontSize, DPI: 72, Hinting: font.HintingFull})
+			if err == nil {
+				return
+			}
+		}
+		log.Printf("No system font for annotations, using built-in face")
+	})
+	return face
+}
@@ -0,0 +1,291 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gonutz/wui/v2"
+	"github.com/package-register/gui/event"
+
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+)
+
+// ApprovalDecision 工具调用审批结果
+type ApprovalDecision int
+
+const (
+	ApprovalDeny        ApprovalDecision = iota // 拒绝本次调用
+	ApprovalApprove                             // 允许本次调用
+	ApprovalAlwaysAllow                         // 允许并记住，此后不再询问
+)
+
+// String 返回审批结果名称
+func (d ApprovalDecision) String() string {
+	switch d {
+	case ApprovalApprove:
+		return "approve"
+	case ApprovalAlwaysAllow:
+		return "always-allow"
+	}
+	return "deny"
+}
+
+// ToolCallRequest 待审批的工具调用
+type ToolCallRequest struct {
+	CallID    string
+	ToolName  string
+	Arguments string // JSON 参数
+}
+
+// ToolApprovalEvent 审批事件数据（event.ToolApproval）
+type ToolApprovalEvent struct {
+	ToolCallRequest
+	Decision   ApprovalDecision
+	Remembered bool // 是否由已记住的决定自动通过
+	Time       time.Time
+}
+
+// ToolApprover 审批回调，阻塞直到用户作出决定；ctx 取消（运行被取消或超时）时应尽快返回 ApprovalDeny
+type ToolApprover func(ctx context.Context, req ToolCallRequest) ApprovalDecision
+
+// toolApproval 敏感工具审批闸门，在 runner 调用工具前暂停等待审批
+type toolApproval struct {
+	mu        sync.Mutex
+	sensitive map[string]bool
+	allowed   map[string]bool // 已选择"总是允许"的工具
+	history   map[string][]ApprovalDecision
+	approver  ToolApprover
+	events    *event.Bus
+}
+
+func newToolApproval(sensitiveTools []string) *toolApproval {
+	g := &toolApproval{
+		sensitive: make(map[string]bool),
+		allowed:   make(map[string]bool),
+		history:   make(map[string][]ApprovalDecision),
+	}
+	for _, name := range sensitiveTools {
+		g.sensitive[name] = true
+	}
+	return g
+}
+
+// callbacks 生成注册到 llmagent 的工具回调
+func (g *toolApproval) callbacks() *tool.Callbacks {
+	return tool.NewCallbacks().RegisterBeforeTool(
+		func(ctx context.Context, args *tool.BeforeToolArgs) (*tool.BeforeToolResult, error) {
+			req := ToolCallRequest{
+				CallID:    args.ToolCallID,
+				ToolName:  args.ToolName,
+				Arguments: string(args.Arguments),
+			}
+			if g.check(ctx, req) {
+				return nil, nil
+			}
+			return &tool.BeforeToolResult{
+				CustomResult: fmt.Sprintf("用户拒绝了工具 %s 的调用", args.ToolName),
+			}, nil
+		})
+}
+
+// check 判断工具调用是否允许执行
+func (g *toolApproval) check(ctx context.Context, req ToolCallRequest) bool {
+	g.mu.Lock()
+	if !g.sensitive[req.ToolName] {
+		g.mu.Unlock()
+		return true
+	}
+	remembered := g.allowed[req.ToolName]
+	approver := g.approver
+	g.mu.Unlock()
+
+	decision := ApprovalDeny
+	switch {
+	case remembered:
+		decision = ApprovalAlwaysAllow
+	case approver != nil:
+		decision = approver(ctx, req)
+	}
+	if ctx.Err() != nil {
+		decision = ApprovalDeny
+	}
+
+	g.mu.Lock()
+	if decision == ApprovalAlwaysAllow {
+		g.allowed[req.ToolName] = true
+	}
+	g.history[req.ToolName] = append(g.history[req.ToolName], decision)
+	events := g.events
+	g.mu.Unlock()
+
+	if events != nil {
+		events.Emit(event.ToolApproval, ToolApprovalEvent{
+			ToolCallRequest: req,
+			Decision:        decision,
+			Remembered:      remembered,
+			Time:            time.Now(),
+		})
+	}
+	return decision != ApprovalDeny
+}
+
+// SetApprover 设置敏感工具的审批回调；未设置时敏感工具一律拒绝
+func (a *AIService) SetApprover(approver ToolApprover) {
+	a.approval.mu.Lock()
+	a.approval.approver = approver
+	a.approval.mu.Unlock()
+}
+
+// SetEventBus 设置审批事件发布的事件总线
+func (a *AIService) SetEventBus(bus *event.Bus) {
+	a.approval.mu.Lock()
+	a.approval.events = bus
+	a.approval.mu.Unlock()
+}
+
+// MarkSensitive 将工具标记为敏感，调用前需要审批
+func (a *AIService) MarkSensitive(toolNames ...string) {
+	a.approval.mu.Lock()
+	for _, name := range toolNames {
+		a.approval.sensitive[name] = true
+	}
+	a.approval.mu.Unlock()
+}
+
+// ApprovalHistory 获取某个工具的历史审批决定
+func (a *AIService) ApprovalHistory(toolName string) []ApprovalDecision {
+	a.approval.mu.Lock()
+	defer a.approval.mu.Unlock()
+	return append([]ApprovalDecision(nil), a.approval.history[toolName]...)
+}
+
+// ResetApprovals 清除所有"总是允许"的记忆
+func (a *AIService) ResetApprovals() {
+	a.approval.mu.Lock()
+	a.approval.allowed = make(map[string]bool)
+	a.approval.mu.Unlock()
+}
+
+// --- ChatPanel 审批栏 ---
+
+// approvalBar 聊天面板内的审批提示栏
+//
+// 界面对话与服务端发起的运行可能同时请求审批，审批栏一次只显示一个请求，其余排队；
+// 每个请求有自己的回复通道，按钮只回复当前显示的请求，重复点击不会留给下一个请求。
+// ask 在 runner 的 goroutine 中调用，控件的更新通过 app.invoke 转交界面线程。
+type approvalBar struct {
+	app   *App
+	panel *wui.Panel
+	label *wui.Label
+	turn  chan struct{} // 容量为 1，持有者占用审批栏
+
+	mu    sync.Mutex
+	reply chan ApprovalDecision // 当前请求的回复通道，未显示时为 nil
+}
+
+// newApprovalBar 创建审批栏（默认隐藏，覆盖在输入区域上）
+func newApprovalBar(app *App, x, y, w, h int) *approvalBar {
+	const buttonWidth = 72
+	const spacing = 8
+
+	bar := &approvalBar{
+		app:   app,
+		panel: wui.NewPanel(),
+		label: wui.NewLabel(),
+		turn:  make(chan struct{}, 1),
+	}
+	bar.panel.SetBounds(x, y, w, h)
+	bar.panel.SetBorderStyle(wui.PanelBorderSingleLine)
+	bar.panel.SetVisible(false)
+
+	labelWidth := w - (buttonWidth+spacing)*3 - spacing
+	bar.label.SetBounds(spacing, 0, labelWidth, h-2)
+	bar.panel.Add(bar.label)
+
+	x = spacing + labelWidth
+	for _, b := range []struct {
+		text     string
+		decision ApprovalDecision
+	}{
+		{"允许", ApprovalApprove},
+		{"拒绝", ApprovalDeny},
+		{"总是允许", ApprovalAlwaysAllow},
+	} {
+		d := b.decision
+		btn := wui.NewButton()
+		btn.SetText(b.text)
+		btn.SetBounds(x, 2, buttonWidth, h-6)
+		btn.SetOnClick(func() { bar.answer(d) })
+		bar.panel.Add(btn)
+		x += buttonWidth + spacing
+	}
+	return bar
+}
+
+// ask 显示审批栏并等待决定，ctx 取消时隐藏审批栏并返回 ApprovalDeny
+func (b *approvalBar) ask(ctx context.Context, req ToolCallRequest) ApprovalDecision {
+	select {
+	case b.turn <- struct{}{}:
+	case <-ctx.Done():
+		return ApprovalDeny
+	}
+	defer func() { <-b.turn }()
+
+	reply := make(chan ApprovalDecision, 1)
+	b.mu.Lock()
+	b.reply = reply
+	b.mu.Unlock()
+	b.app.invoke(func() {
+		b.label.SetText(fmt.Sprintf("调用工具 %s %s", req.ToolName, req.Arguments))
+		b.panel.SetVisible(true)
+	})
+	defer func() {
+		b.mu.Lock()
+		b.reply = nil
+		b.mu.Unlock()
+		b.app.invoke(func() { b.panel.SetVisible(false) })
+	}()
+
+	select {
+	case decision := <-reply:
+		return decision
+	case <-ctx.Done():
+		return ApprovalDeny
+	}
+}
+
+// answer 回复当前显示的请求，没有请求或已回复时忽略
+func (b *approvalBar) answer(d ApprovalDecision) {
+	b.mu.Lock()
+	reply := b.reply
+	b.reply = nil
+	b.mu.Unlock()
+	if reply != nil {
+		reply <- d
+	}
+}
+
+// approveToolCall ChatPanel 的默认审批回调
+func (c *ChatPanel) approveToolCall(ctx context.Context, req ToolCallRequest) ApprovalDecision {
+	c.appendSystemMessage(fmt.Sprintf("助手请求调用工具 %s，参数: %s", req.ToolName, req.Arguments))
+
+	var decision ApprovalDecision
+	if c.approval != nil {
+		decision = c.approval.ask(ctx, req)
+	} else if wui.MessageBoxYesNo("工具调用审批", fmt.Sprintf("允许调用工具 %s？\n\n参数: %s", req.ToolName, req.Arguments)) {
+		decision = ApprovalApprove
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		c.appendSystemMessage("运行已取消，未调用工具: " + req.ToolName)
+		return ApprovalDeny
+	case decision == ApprovalDeny:
+		c.appendSystemMessage("已拒绝工具调用: " + req.ToolName)
+	default:
+		c.appendSystemMessage("已允许工具调用: " + req.ToolName)
+	}
+	return decision
+}
@@ -0,0 +1,177 @@
+package sdk
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"time"
+
+	"github.com/kbinani/screenshot"
+)
+
+// CaptureKind 截图目标类型
+type CaptureKind int
+
+const (
+	CaptureKindDisplay CaptureKind = iota // 单个显示器
+	CaptureKindAll                        // 所有显示器拼接成的虚拟桌面
+	CaptureKindRect                       // 任意屏幕矩形
+)
+
+// CaptureTarget 截图目标
+type CaptureTarget struct {
+	Kind    CaptureKind
+	Display int             // CaptureKindDisplay 时的显示器序号
+	Rect    image.Rectangle // CaptureKindRect 时的屏幕坐标矩形
+}
+
+// CaptureDisplay 截取指定显示器
+func CaptureDisplay(index int) CaptureTarget {
+	return CaptureTarget{Kind: CaptureKindDisplay, Display: index}
+}
+
+// CaptureAllDisplays 截取所有显示器并拼接为一张虚拟桌面图片
+func CaptureAllDisplays() CaptureTarget {
+	return CaptureTarget{Kind: CaptureKindAll}
+}
+
+// CaptureRect 截取任意屏幕矩形（虚拟桌面坐标）
+func CaptureRect(rect image.Rectangle) CaptureTarget {
+	return CaptureTarget{Kind: CaptureKindRect, Rect: rect}
+}
+
+// String 返回目标描述
+func (c CaptureTarget) String() string {
+	switch c.Kind {
+	case CaptureKindAll:
+		return "all"
+	case CaptureKindRect:
+		return fmt.Sprintf("rect(%d,%d %dx%d)", c.Rect.Min.X, c.Rect.Min.Y, c.Rect.Dx(), c.Rect.Dy())
+	}
+	return fmt.Sprintf("display%d", c.Display)
+}
+
+// DisplayInfo 显示器信息
+type DisplayInfo struct {
+	Index   int
+	Bounds  image.Rectangle // 虚拟桌面坐标
+	Primary bool
+}
+
+// Displays 枚举所有活动显示器
+func Displays() []DisplayInfo {
+	n := screenshot.NumActiveDisplays()
+	displays := make([]DisplayInfo, 0, n)
+	for i := 0; i < n; i++ {
+		bounds := screenshot.GetDisplayBounds(i)
+		displays = append(displays, DisplayInfo{
+			Index:   i,
+			Bounds:  bounds,
+			Primary: bounds.Min == image.Point{},
+		})
+	}
+	return displays
+}
+
+// VirtualDesktopBounds 所有显示器组成的虚拟桌面范围
+func VirtualDesktopBounds() image.Rectangle {
+	var union image.Rectangle
+	for _, d := range Displays() {
+		union = union.Union(d.Bounds)
+	}
+	return union
+}
+
+// Screenshot 截图结果，携带截图元数据，可直接作为 image.Image 使用
+type Screenshot struct {
+	*image.RGBA
+	Target       CaptureTarget
+	Display      int             // 显示器序号，跨显示器时为 -1
+	ScreenBounds image.Rectangle // 截图在虚拟桌面中的范围
+	Time         time.Time
+	Redactions   []image.Rectangle // 已打码的区域（图片坐标）
+
+	redacted bool // 是否已经过打码管道
+}
+
+// ScreenshotInfo 从图片中取出截图元数据
+func ScreenshotInfo(img image.Image) (*Screenshot, bool) {
+	s, ok := img.(*Screenshot)
+	return s, ok
+}
+
+// Capture 按目标截图
+func Capture(target CaptureTarget) (*Screenshot, error) {
+	shot := &Screenshot{Target: target, Display: -1, Time: time.Now()}
+
+	switch target.Kind {
+	case CaptureKindDisplay:
+		n := screenshot.NumActiveDisplays()
+		if target.Display < 0 || target.Display >= n {
+			return nil, fmt.Errorf("显示器 %d 不存在（共 %d 个）", target.Display, n)
+		}
+		shot.Display = target.Display
+		shot.ScreenBounds = screenshot.GetDisplayBounds(target.Display)
+
+	case CaptureKindAll:
+		return captureVirtualDesktop(shot)
+
+	case CaptureKindRect:
+		if target.Rect.Empty() {
+			return nil, fmt.Errorf("截图区域为空")
+		}
+		shot.ScreenBounds = target.Rect
+		shot.Display = displayContaining(target.Rect)
+
+	default:
+		return nil, fmt.Errorf("未知截图目标: %d", target.Kind)
+	}
+
+	img, err := screenshot.CaptureRect(shot.ScreenBounds)
+	if err != nil {
+		return nil, err
+	}
+	shot.RGBA = img
+	return shot, nil
+}
+
+// displayContaining 返回完整包含矩形的显示器序号，跨显示器时为 -1
+func displayContaining(rect image.Rectangle) int {
+	for _, d := range Displays() {
+		if rect.In(d.Bounds) {
+			return d.Index
+		}
+	}
+	return -1
+}
+
+// captureVirtualDesktop 分别截取每个显示器，再拼接到虚拟桌面画布上
+func captureVirtualDesktop(shot *Screenshot) (*Screenshot, error) {
+	displays := Displays()
+	if len(displays) == 0 {
+		return nil, fmt.Errorf("没有可用的显示器")
+	}
+
+	var union image.Rectangle
+	for _, d := range displays {
+		union = union.Union(d.Bounds)
+	}
+
+	// 画布以虚拟桌面左上角为原点，显示器之间的空隙保持透明
+	canvas := image.NewRGBA(image.Rect(0, 0, union.Dx(), union.Dy()))
+	for _, d := range displays {
+		img, err := screenshot.CaptureRect(d.Bounds)
+		if err != nil {
+			return nil, fmt.Errorf("截取显示器 %d 失败: %w", d.Index, err)
+		}
+		dst := d.Bounds.Sub(union.Min)
+		draw.Draw(canvas, dst, img, img.Bounds().Min, draw.Src)
+	}
+
+	shot.RGBA = canvas
+	shot.ScreenBounds = union
+	if len(displays) == 1 {
+		shot.Display = 0
+	}
+	return shot, nil
+}
@@ -0,0 +1,422 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/package-register/gui/event"
+)
+
+// ChatRole 消息角色
+type ChatRole string
+
+const (
+	ChatRoleUser      ChatRole = "user"
+	ChatRoleAssistant ChatRole = "assistant"
+	ChatRoleSystem    ChatRole = "system"
+)
+
+// ExportFormat 对话导出格式
+type ExportFormat string
+
+const (
+	ExportMarkdown ExportFormat = "md"
+	ExportHTML     ExportFormat = "html"
+	ExportJSON     ExportFormat = "json"
+)
+
+// conversationVersion JSON 导出格式版本
+const conversationVersion = 1
+
+// ChatMessage 一条结构化聊天消息
+type ChatMessage struct {
+	Role      ChatRole
+	Content   string
+	Timestamp time.Time
+	Images    []image.Image // 附带的截图
+}
+
+// Conversation 一次完整对话
+type Conversation struct {
+	Title      string
+	ExportedAt time.Time
+	Messages   []ChatMessage
+}
+
+// --- JSON 结构（可重新导入） ---
+
+type conversationJSON struct {
+	Version    int           `json:"version"`
+	Title      string        `json:"title"`
+	ExportedAt time.Time     `json:"exported_at"`
+	Messages   []messageJSON `json:"messages"`
+}
+
+type messageJSON struct {
+	Role        ChatRole         `json:"role"`
+	Content     string           `json:"content"`
+	Timestamp   time.Time        `json:"timestamp"`
+	Attachments []attachmentJSON `json:"attachments,omitempty"`
+}
+
+type attachmentJSON struct {
+	MimeType string `json:"mime_type"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Data     string `json:"data"` // base64 编码的 PNG
+}
+
+// ParseExportFormat 从字符串或文件扩展名解析导出格式
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch strings.ToLower(strings.TrimPrefix(s, ".")) {
+	case "md", "markdown":
+		return ExportMarkdown, nil
+	case "html", "htm":
+		return ExportHTML, nil
+	case "json":
+		return ExportJSON, nil
+	}
+	return "", fmt.Errorf("不支持的导出格式: %s", s)
+}
+
+// ExportConversation 将对话按指定格式写入 w
+func ExportConversation(w io.Writer, conv *Conversation, format ExportFormat) error {
+	switch format {
+	case ExportMarkdown:
+		return exportMarkdown(w, conv)
+	case ExportHTML:
+		return exportHTML(w, conv)
+	case ExportJSON:
+		return exportJSON(w, conv)
+	}
+	return fmt.Errorf("不支持的导出格式: %s", format)
+}
+
+// ImportConversation 从 JSON 导出文件中读取对话
+func ImportConversation(r io.Reader) (*Conversation, error) {
+	var doc conversationJSON
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("解析对话失败: %w", err)
+	}
+	if doc.Version > conversationVersion {
+		return nil, fmt.Errorf("不支持的对话版本: %d", doc.Version)
+	}
+
+	conv := &Conversation{Title: doc.Title, ExportedAt: doc.ExportedAt}
+	for i, m := range doc.Messages {
+		msg := ChatMessage{Role: m.Role, Content: m.Content, Timestamp: m.Timestamp}
+		for j, a := range m.Attachments {
+			data, err := base64.StdEncoding.DecodeString(a.Data)
+			if err != nil {
+				return nil, fmt.Errorf("消息 %d 附件 %d 解码失败: %w", i, j, err)
+			}
+			img, err := png.Decode(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("消息 %d 附件 %d 解码失败: %w", i, j, err)
+			}
+			msg.Images = append(msg.Images, img)
+		}
+		conv.Messages = append(conv.Messages, msg)
+	}
+	return conv, nil
+}
+
+// ImportConversationFile 从文件读取对话
+func ImportConversationFile(filename string) (*Conversation, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ImportConversation(f)
+}
+
+func exportJSON(w io.Writer, conv *Conversation) error {
+	doc := conversationJSON{
+		Version:    conversationVersion,
+		Title:      conv.Title,
+		ExportedAt: conv.ExportedAt,
+		Messages:   make([]messageJSON, 0, len(conv.Messages)),
+	}
+	for _, m := range conv.Messages {
+		msg := messageJSON{Role: m.Role, Content: m.Content, Timestamp: m.Timestamp}
+		for _, img := range m.Images {
+			data, err := encodePNGBase64(img)
+			if err != nil {
+				return err
+			}
+			b := img.Bounds()
+			msg.Attachments = append(msg.Attachments, attachmentJSON{
+				MimeType: "image/png",
+				Width:    b.Dx(),
+				Height:   b.Dy(),
+				Data:     data,
+			})
+		}
+		doc.Messages = append(doc.Messages, msg)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func exportMarkdown(w io.Writer, conv *Conversation) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n\n", conv.Title)
+	fmt.Fprintf(&buf, "> 导出时间: %s\n\n", conv.ExportedAt.Format("2006-01-02 15:04:05"))
+
+	for _, m := range conv.Messages {
+		fmt.Fprintf(&buf, "### %s · %s\n\n", roleDisplayName(m.Role), m.Timestamp.Format("2006-01-02 15:04:05"))
+		buf.WriteString(m.Content)
+		buf.WriteString("\n\n")
+		for i, img := range m.Images {
+			data, err := encodePNGBase64(img)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&buf, "![截图 %d](data:image/png;base64,%s)\n\n", i+1, data)
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+var conversationHTML = template.Must(template.New("conversation").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: "Microsoft YaHei", sans-serif; background: #FAFAFA; color: #212121; max-width: 860px; margin: 24px auto; }
+.msg { background: #FFFFFF; border: 1px solid #C8C8C8; border-radius: 4px; padding: 12px 16px; margin: 12px 0; }
+.msg.user { border-left: 4px solid #6750A4; }
+.msg.assistant { border-left: 4px solid #B39DDB; }
+.msg.system { color: #757575; font-size: 0.9em; }
+.meta { color: #757575; font-size: 0.85em; margin-bottom: 6px; }
+.content { white-space: pre-wrap; }
+img { max-width: 100%; border: 1px solid #C8C8C8; margin-top: 8px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">导出时间: {{.ExportedAt}}</p>
+{{range .Messages}}<div class="msg {{.Role}}">
+<div class="meta">{{.Name}} · {{.Time}}</div>
+<div class="content">{{.Content}}</div>
+{{range .Images}}<img src="{{.}}">
+{{end}}</div>
+{{end}}</body>
+</html>
+`))
+
+func exportHTML(w io.Writer, conv *Conversation) error {
+	type htmlMessage struct {
+		Role    ChatRole
+		Name    string
+		Time    string
+		Content string
+		Images  []template.URL
+	}
+	data := struct {
+		Title      string
+		ExportedAt string
+		Messages   []htmlMessage
+	}{
+		Title:      conv.Title,
+		ExportedAt: conv.ExportedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	for _, m := range conv.Messages {
+		msg := htmlMessage{
+			Role:    m.Role,
+			Name:    roleDisplayName(m.Role),
+			Time:    m.Timestamp.Format("2006-01-02 15:04:05"),
+			Content: m.Content,
+		}
+		for _, img := range m.Images {
+			encoded, err := encodePNGBase64(img)
+			if err != nil {
+				return err
+			}
+			msg.Images = append(msg.Images, template.URL("data:image/png;base64,"+encoded))
+		}
+		data.Messages = append(data.Messages, msg)
+	}
+
+	return conversationHTML.Execute(w, data)
+}
+
+func encodePNGBase64(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", fmt.Errorf("图片编码失败: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func roleDisplayName(role ChatRole) string {
+	switch role {
+	case ChatRoleUser:
+		return "用户"
+	case ChatRoleAssistant:
+		return "AI"
+	case ChatRoleSystem:
+		return "系统"
+	}
+	return string(role)
+}
+
+// --- ChatPanel 导出相关 ---
+
+// record 记录一条结构化消息
+func (c *ChatPanel) record(role ChatRole, content string, images []image.Image) {
+	c.mu.Lock()
+	c.messages = append(c.messages, ChatMessage{
+		Role:      role,
+		Content:   content,
+		Timestamp: time.Now(),
+		Images:    images,
+	})
+	c.mu.Unlock()
+}
+
+// AttachImage 附加一张图片（如截图），随下一条用户消息一起记录
+func (c *ChatPanel) AttachImage(img image.Image) {
+	if img == nil {
+		return
+	}
+	c.mu.Lock()
+	redactor := c.redactor
+	c.mu.Unlock()
+	// 已打码的截图可能只经过了其他打码规则，重复打码不会改变已遮盖的区域
+	if redactor != nil {
+		redacted, err := redactor.Redact(img)
+		if err != nil {
+			c.appendSystemMessage(fmt.Sprintf("截图打码失败，已拒绝附加: %v", err))
+			return
+		}
+		img = redacted
+	}
+
+	c.mu.Lock()
+	c.attachments = append(c.attachments, img)
+	c.mu.Unlock()
+	b := img.Bounds()
+	c.appendSystemMessage(fmt.Sprintf("已附加截图 %dx%d，将随下一条消息发送", b.Dx(), b.Dy()))
+}
+
+// SetRedactor 设置附件打码管道，设置后所有截图在附加前都按此管道打码
+func (c *ChatPanel) SetRedactor(r *Redactor) {
+	c.mu.Lock()
+	c.redactor = r
+	c.mu.Unlock()
+}
+
+// Messages 获取结构化消息列表副本
+func (c *ChatPanel) Messages() []ChatMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ChatMessage(nil), c.messages...)
+}
+
+// Conversation 获取当前对话
+func (c *ChatPanel) Conversation() *Conversation {
+	return &Conversation{
+		Title:      "对话记录",
+		ExportedAt: time.Now(),
+		Messages:   c.Messages(),
+	}
+}
+
+// Export 导出对话到 w
+func (c *ChatPanel) Export(w io.Writer, format ExportFormat) error {
+	return ExportConversation(w, c.Conversation(), format)
+}
+
+// ExportToFile 导出对话到文件，filename 为空时按时间生成文件名
+func (c *ChatPanel) ExportToFile(filename string, format ExportFormat) (string, error) {
+	if filename == "" {
+		filename = fmt.Sprintf("chat_%s.%s", time.Now().Format("20060102_150405"), format)
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return "", err
+	}
+	if err := c.Export(f, format); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	if c.events != nil {
+		c.events.Emit(event.ChatExport, filename)
+	}
+	return filename, nil
+}
+
+// LoadConversation 载入对话（如从 JSON 导入），替换当前历史
+func (c *ChatPanel) LoadConversation(conv *Conversation) {
+	var text strings.Builder
+	for _, m := range conv.Messages {
+		ts := m.Timestamp.Format("15:04:05")
+		if m.Role == ChatRoleSystem {
+			fmt.Fprintf(&text, "\n[%s] 系统: %s\n\n", ts, m.Content)
+			continue
+		}
+		fmt.Fprintf(&text, "\n[%s] %s:\n%s\n\n", ts, roleDisplayName(m.Role), m.Content)
+	}
+
+	c.mu.Lock()
+	c.messages = append([]ChatMessage(nil), conv.Messages...)
+	c.mu.Unlock()
+	c.history.SetText(text.String())
+}
+
+// runCommand 执行斜杠命令，返回是否已处理
+//
+//	/export [md|html|json] [文件名]
+func (c *ChatPanel) runCommand(line string) bool {
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case "/export":
+		format := ExportMarkdown
+		filename := ""
+		if len(fields) > 1 {
+			f, err := ParseExportFormat(fields[1])
+			if err != nil {
+				c.appendSystemMessage("❌ " + err.Error())
+				return true
+			}
+			format = f
+		}
+		if len(fields) > 2 {
+			filename = fields[2]
+			if filepath.Ext(filename) == "" {
+				filename += "." + string(format)
+			}
+		}
+		saved, err := c.ExportToFile(filename, format)
+		if err != nil {
+			c.appendSystemMessage("❌ 导出失败: " + err.Error())
+		} else {
+			c.appendSystemMessage("对话已导出: " + saved)
+		}
+		return true
+	}
+	return false
+}
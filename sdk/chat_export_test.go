@@ -0,0 +1,129 @@
+package sdk
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gonutz/wui/v2"
+)
+
+// testScreenshot 左上角为红色像素的 4×3 图片
+func testScreenshot() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	img.SetRGBA(0, 0, color.RGBA{R: 0xff, A: 0xff})
+	return img
+}
+
+// roundTrip 导出为 JSON 后重新导入
+func roundTrip(t *testing.T, conv *Conversation) *Conversation {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := ExportConversation(&buf, conv, ExportJSON); err != nil {
+		t.Fatalf("导出 JSON 出错: %v", err)
+	}
+	imported, err := ImportConversation(&buf)
+	if err != nil {
+		t.Fatalf("导入 JSON 出错: %v", err)
+	}
+	return imported
+}
+
+func TestConversationJSONRoundTrip(t *testing.T) {
+	at := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	conv := &Conversation{
+		Title:      "测试对话",
+		ExportedAt: at.Add(time.Hour),
+		Messages: []ChatMessage{
+			{Role: ChatRoleSystem, Content: "你是截图助手", Timestamp: at},
+			{Role: ChatRoleUser, Content: "看看这张截图\n第二行", Timestamp: at.Add(time.Second), Images: []image.Image{testScreenshot()}},
+			{Role: ChatRoleAssistant, Content: "左上角有一个<红色>像素", Timestamp: at.Add(2 * time.Second)},
+		},
+	}
+
+	got := roundTrip(t, conv)
+	if got.Title != conv.Title || !got.ExportedAt.Equal(conv.ExportedAt) {
+		t.Errorf("标题与导出时间 = %q %v，期望 %q %v", got.Title, got.ExportedAt, conv.Title, conv.ExportedAt)
+	}
+	if len(got.Messages) != len(conv.Messages) {
+		t.Fatalf("导入 %d 条消息，期望 %d 条", len(got.Messages), len(conv.Messages))
+	}
+	for i, want := range conv.Messages {
+		m := got.Messages[i]
+		if m.Role != want.Role || m.Content != want.Content || !m.Timestamp.Equal(want.Timestamp) {
+			t.Errorf("消息 %d = %s %q %v，期望 %s %q %v", i, m.Role, m.Content, m.Timestamp, want.Role, want.Content, want.Timestamp)
+		}
+		if len(m.Images) != len(want.Images) {
+			t.Fatalf("消息 %d 有 %d 张图片，期望 %d 张", i, len(m.Images), len(want.Images))
+		}
+	}
+
+	img := got.Messages[1].Images[0]
+	if img.Bounds() != image.Rect(0, 0, 4, 3) {
+		t.Fatalf("导入的图片尺寸 = %v", img.Bounds())
+	}
+	if r, g, _, _ := img.At(0, 0).RGBA(); r != 0xffff || g != 0 {
+		t.Errorf("导入的图片 (0,0) = %v，期望红色", img.At(0, 0))
+	}
+	if r, g, b, _ := img.At(3, 2).RGBA(); r != 0xffff || g != 0xffff || b != 0xffff {
+		t.Errorf("导入的图片 (3,2) = %v，期望白色", img.At(3, 2))
+	}
+
+	// 再次导出的结果不变
+	again := roundTrip(t, got)
+	if len(again.Messages) != len(conv.Messages) || again.Messages[2].Content != conv.Messages[2].Content {
+		t.Errorf("二次导入的消息 = %v", again.Messages)
+	}
+}
+
+func TestChatPanelExportExcludesSystemLines(t *testing.T) {
+	c := &ChatPanel{history: wui.NewTextEdit(), input: wui.NewEditLine()}
+	c.AttachImage(testScreenshot())
+	c.SendMessage("看看这张截图")
+	c.appendSystemMessage("AI 正在生成回复...")
+	c.record(ChatRoleAssistant, "这是一张白色图片", nil)
+	c.appendSystemMessage("对话已导出: chat.json")
+
+	// 临时状态只显示在历史中
+	history := c.GetHistory()
+	for _, line := range []string{"已附加截图 4x3", "AI 正在生成回复...", "对话已导出"} {
+		if !strings.Contains(history, line) {
+			t.Errorf("聊天历史中缺少系统提示 %q", line)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := c.Export(&buf, ExportJSON); err != nil {
+		t.Fatalf("导出出错: %v", err)
+	}
+	conv, err := ImportConversation(&buf)
+	if err != nil {
+		t.Fatalf("导入出错: %v", err)
+	}
+	if len(conv.Messages) != 2 {
+		t.Fatalf("导入 %d 条消息 %v，期望只有用户与助手两条", len(conv.Messages), conv.Messages)
+	}
+	user, assistant := conv.Messages[0], conv.Messages[1]
+	if user.Role != ChatRoleUser || user.Content != "看看这张截图" || len(user.Images) != 1 {
+		t.Errorf("用户消息 = %s %q，%d 张图片，期望带一张附件", user.Role, user.Content, len(user.Images))
+	}
+	if assistant.Role != ChatRoleAssistant || assistant.Content != "这是一张白色图片" {
+		t.Errorf("助手消息 = %s %q", assistant.Role, assistant.Content)
+	}
+
+	// 导入的对话载入后再导出，消息保持一致
+	loaded := &ChatPanel{history: wui.NewTextEdit(), input: wui.NewEditLine()}
+	loaded.LoadConversation(conv)
+	if got := loaded.Messages(); len(got) != 2 || got[1].Content != assistant.Content {
+		t.Errorf("载入后的消息 = %v", got)
+	}
+	if strings.Contains(loaded.GetHistory(), "AI 正在生成回复") {
+		t.Error("载入的对话不应包含临时系统提示")
+	}
+}
@@ -0,0 +1,111 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gonutz/wui/v2"
+)
+
+// 快捷键列表布局
+const (
+	cheatsheetPadding     = 20
+	cheatsheetTitleHeight = 25
+	cheatsheetButtonWidth = 100
+	cheatsheetButtonH     = 30
+)
+
+// cheatsheet 覆盖在内容区上的快捷键列表
+type cheatsheet struct {
+	panel   *wui.Panel
+	list    *wui.TextEdit
+	visible bool
+}
+
+// buildCheatsheet 创建快捷键列表面板，默认隐藏
+func (app *App) buildCheatsheet() {
+	w, h := app.width, app.height-app.contentY
+	c := &cheatsheet{panel: wui.NewPanel(), list: wui.NewTextEdit()}
+
+	title := wui.NewLabel()
+	title.SetText("快捷键（按 Esc 或 F1 关闭）")
+	title.SetBounds(cheatsheetPadding, cheatsheetPadding, w-cheatsheetPadding*2, cheatsheetTitleHeight)
+	c.panel.Add(title)
+
+	listY := cheatsheetPadding*2 + cheatsheetTitleHeight
+	listH := h - listY - cheatsheetButtonH - cheatsheetPadding*2
+	c.list.SetBounds(cheatsheetPadding, listY, w-cheatsheetPadding*2, listH)
+	c.list.SetReadOnly(true)
+	c.panel.Add(c.list)
+
+	closeBtn := wui.NewButton()
+	closeBtn.SetText("关闭")
+	closeBtn.SetBounds(w-cheatsheetPadding-cheatsheetButtonWidth, h-cheatsheetPadding-cheatsheetButtonH, cheatsheetButtonWidth, cheatsheetButtonH)
+	closeBtn.SetOnClick(app.HideShortcuts)
+	c.panel.Add(closeBtn)
+
+	app.window.Add(c.panel)
+	c.panel.SetBounds(0, app.contentY, 0, 0)
+	app.cheatsheet = c
+}
+
+// ShowShortcuts 在内容区显示当前 Tab 下有效的快捷键列表
+func (app *App) ShowShortcuts() {
+	c := app.cheatsheet
+	if c == nil || c.visible {
+		return
+	}
+	c.list.SetText(FormatShortcuts(app.Shortcuts()))
+	if cur, ok := app.tabs[app.activeTab]; ok {
+		cur.hide()
+	}
+	c.panel.SetBounds(0, app.contentY, app.width, app.height-app.contentY)
+	c.visible = true
+}
+
+// HideShortcuts 关闭快捷键列表，恢复当前 Tab
+func (app *App) HideShortcuts() {
+	c := app.cheatsheet
+	if c == nil || !c.visible {
+		return
+	}
+	c.panel.SetBounds(0, app.contentY, 0, 0)
+	c.visible = false
+	if cur, ok := app.tabs[app.activeTab]; ok {
+		cur.show()
+		cur.restoreFocus()
+	}
+}
+
+// ToggleShortcuts 切换快捷键列表
+func (app *App) ToggleShortcuts() {
+	if app.cheatsheet != nil && app.cheatsheet.visible {
+		app.HideShortcuts()
+	} else {
+		app.ShowShortcuts()
+	}
+}
+
+// FormatShortcuts 将快捷键格式化为列表文字，按所属 Tab 分组，未绑定的不列出
+func FormatShortcuts(shortcuts []Shortcut) string {
+	var sb strings.Builder
+	group := "\x00"
+	for _, s := range shortcuts {
+		if !s.Bound() {
+			continue
+		}
+		if s.Tab != group {
+			if sb.Len() > 0 {
+				sb.WriteString("\n")
+			}
+			group = s.Tab
+			if group == "" {
+				sb.WriteString("全局\n")
+			} else {
+				sb.WriteString(group + "\n")
+			}
+		}
+		fmt.Fprintf(&sb, "  %-16s\t%s\n", s.Accelerator, s.Description)
+	}
+	return sb.String()
+}
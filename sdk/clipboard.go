@@ -0,0 +1,299 @@
+package sdk
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/package-register/gui/event"
+)
+
+// ClipboardFormat 剪贴板内容类型
+type ClipboardFormat int
+
+const (
+	ClipboardEmpty ClipboardFormat = iota
+	ClipboardText
+	ClipboardImage
+	ClipboardFiles
+)
+
+// String 返回内容类型名称
+func (f ClipboardFormat) String() string {
+	switch f {
+	case ClipboardText:
+		return "text"
+	case ClipboardImage:
+		return "image"
+	case ClipboardFiles:
+		return "files"
+	}
+	return "empty"
+}
+
+// clipboardPollInterval 检测剪贴板变化的间隔
+const clipboardPollInterval = 500 * time.Millisecond
+
+// ClipboardBackend 剪贴板后端
+type ClipboardBackend interface {
+	Text() (string, error)
+	SetText(text string) error
+	Image() (image.Image, error)
+	SetImage(img image.Image) error
+	Files() ([]string, error)
+	SetFiles(paths []string) error
+	// Format 当前内容的主要类型
+	Format() ClipboardFormat
+	// Sequence 内容每次变化都会递增的序号
+	Sequence() uint32
+}
+
+// ClipboardChange 剪贴板变化事件的数据
+type ClipboardChange struct {
+	Format   ClipboardFormat
+	Sequence uint32
+	Local    bool // 由本应用写入
+}
+
+// Clipboard 剪贴板服务：读写文字、图片与文件列表，并在内容变化时发布 event.ClipboardChanged
+type Clipboard struct {
+	backend ClipboardBackend
+	events  *event.Bus
+
+	mu      sync.Mutex
+	lastSeq uint32
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewClipboard 创建剪贴板服务，events 可为 nil
+func NewClipboard(backend ClipboardBackend, events *event.Bus) *Clipboard {
+	return &Clipboard{backend: backend, events: events, lastSeq: backend.Sequence()}
+}
+
+// WithClipboard 替换应用的剪贴板后端（如测试中使用 NewMemoryClipboard）
+func WithClipboard(backend ClipboardBackend) Option {
+	return func(a *App) { a.clipboard = NewClipboard(backend, a.events) }
+}
+
+// Clipboard 应用的剪贴板服务
+func (app *App) Clipboard() *Clipboard {
+	return app.clipboard
+}
+
+// Text 读取文字
+func (c *Clipboard) Text() (string, error) {
+	return c.backend.Text()
+}
+
+// SetText 写入文字
+func (c *Clipboard) SetText(text string) error {
+	return c.write(c.backend.SetText(text))
+}
+
+// Image 读取图片
+func (c *Clipboard) Image() (image.Image, error) {
+	return c.backend.Image()
+}
+
+// SetImage 写入图片
+func (c *Clipboard) SetImage(img image.Image) error {
+	if img == nil {
+		return ErrNoImage
+	}
+	return c.write(c.backend.SetImage(img))
+}
+
+// Files 读取文件列表
+func (c *Clipboard) Files() ([]string, error) {
+	return c.backend.Files()
+}
+
+// SetFiles 写入文件列表
+func (c *Clipboard) SetFiles(paths []string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("文件列表为空")
+	}
+	return c.write(c.backend.SetFiles(paths))
+}
+
+// Format 当前内容的主要类型
+func (c *Clipboard) Format() ClipboardFormat {
+	return c.backend.Format()
+}
+
+// write 写入成功后立即发布变化事件
+func (c *Clipboard) write(err error) error {
+	if err != nil {
+		return err
+	}
+	c.check(true)
+	return nil
+}
+
+// check 序号变化时发布事件
+func (c *Clipboard) check(local bool) {
+	seq := c.backend.Sequence()
+	c.mu.Lock()
+	changed := seq != c.lastSeq
+	c.lastSeq = seq
+	c.mu.Unlock()
+	if changed && c.events != nil {
+		c.events.Emit(event.ClipboardChanged, ClipboardChange{Format: c.backend.Format(), Sequence: seq, Local: local})
+	}
+}
+
+// Watch 开始检测其他程序对剪贴板的修改，重复调用无效
+func (c *Clipboard) Watch() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop != nil {
+		return
+	}
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.poll(c.stop, c.done)
+}
+
+// Stop 停止检测
+func (c *Clipboard) Stop() {
+	c.mu.Lock()
+	stop, done := c.stop, c.done
+	c.stop, c.done = nil, nil
+	c.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+func (c *Clipboard) poll(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(clipboardPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.check(false)
+		}
+	}
+}
+
+// WithCopyToClipboard 截图完成后（打码之后）将图片复制到剪贴板
+func WithCopyToClipboard() ScreenshotOption {
+	return func(c *screenshotConfig) { c.copyImage = true }
+}
+
+// CopyToClipboard 将当前图片复制到剪贴板
+func (img *ImageDisplay) CopyToClipboard() error {
+	if img.image == nil {
+		return ErrNoImage
+	}
+	return img.app.clipboard.SetImage(img.image)
+}
+
+// CopyLastReply 将最近一条助手回复复制到剪贴板
+func (c *ChatPanel) CopyLastReply() error {
+	c.mu.Lock()
+	var reply string
+	for i := len(c.messages) - 1; i >= 0; i-- {
+		if c.messages[i].Role == ChatRoleAssistant {
+			reply = c.messages[i].Content
+			break
+		}
+	}
+	c.mu.Unlock()
+	if reply == "" {
+		return fmt.Errorf("没有可复制的回复")
+	}
+	return c.app.clipboard.SetText(reply)
+}
+
+// copyScreenshot 截图流程中的复制动作
+func (t *TabContext) copyScreenshot(img image.Image) {
+	if err := t.app.clipboard.SetImage(img); err != nil {
+		log.Printf("Copy screenshot to clipboard failed: %v", err)
+	}
+}
+
+// MemoryClipboard 进程内的剪贴板后端，行为确定，用于测试
+type MemoryClipboard struct {
+	mu     sync.Mutex
+	format ClipboardFormat
+	text   string
+	image  image.Image
+	files  []string
+	seq    uint32
+}
+
+// NewMemoryClipboard 创建进程内剪贴板
+func NewMemoryClipboard() *MemoryClipboard {
+	return &MemoryClipboard{}
+}
+
+func (m *MemoryClipboard) Text() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.format != ClipboardText {
+		return "", fmt.Errorf("剪贴板中没有文字")
+	}
+	return m.text, nil
+}
+
+func (m *MemoryClipboard) SetText(text string) error {
+	m.set(func() { m.format, m.text = ClipboardText, text })
+	return nil
+}
+
+func (m *MemoryClipboard) Image() (image.Image, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.format != ClipboardImage {
+		return nil, fmt.Errorf("剪贴板中没有图片")
+	}
+	return m.image, nil
+}
+
+func (m *MemoryClipboard) SetImage(img image.Image) error {
+	m.set(func() { m.format, m.image = ClipboardImage, img })
+	return nil
+}
+
+func (m *MemoryClipboard) Files() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.format != ClipboardFiles {
+		return nil, fmt.Errorf("剪贴板中没有文件")
+	}
+	return append([]string(nil), m.files...), nil
+}
+
+func (m *MemoryClipboard) SetFiles(paths []string) error {
+	m.set(func() { m.format, m.files = ClipboardFiles, append([]string(nil), paths...) })
+	return nil
+}
+
+func (m *MemoryClipboard) Format() ClipboardFormat {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.format
+}
+
+func (m *MemoryClipboard) Sequence() uint32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.seq
+}
+
+// set 清空旧内容后写入，与系统剪贴板一致
+func (m *MemoryClipboard) set(write func()) {
+	m.mu.Lock()
+	m.text, m.image, m.files = "", nil, nil
+	write()
+	m.seq++
+	m.mu.Unlock()
+}
@@ -0,0 +1,288 @@
+package sdk
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"syscall"
+	"time"
+	"unsafe"
+
+	w32 "github.com/gonutz/w32/v2"
+)
+
+var procGetClipboardSequenceNumber = syscall.NewLazyDLL("user32.dll").NewProc("GetClipboardSequenceNumber")
+
+// 剪贴板打开重试：其他程序可能短暂占用剪贴板
+const (
+	clipboardOpenRetries = 10
+	clipboardRetryDelay  = 10 * time.Millisecond
+)
+
+// BITMAPINFOHEADER 大小与 DROPFILES 头部大小
+const (
+	dibHeaderSize  = 40
+	dropFilesSize  = 20
+	biBitfields    = 3
+	dibBytesPerPix = 4
+)
+
+// systemClipboard Windows 系统剪贴板：文字 CF_UNICODETEXT，图片 CF_DIB，文件 CF_HDROP
+type systemClipboard struct{}
+
+// open 打开剪贴板，失败时短暂重试
+func (systemClipboard) open() error {
+	for i := 0; i < clipboardOpenRetries; i++ {
+		if w32.OpenClipboard(0) {
+			return nil
+		}
+		time.Sleep(clipboardRetryDelay)
+	}
+	return fmt.Errorf("打开剪贴板失败")
+}
+
+// read 读取 format 格式的数据副本
+func (c systemClipboard) read(format uint) ([]byte, error) {
+	if err := c.open(); err != nil {
+		return nil, err
+	}
+	defer w32.CloseClipboard()
+	if !w32.IsClipboardFormatAvailable(format) {
+		return nil, fmt.Errorf("剪贴板中没有所需格式的数据")
+	}
+	mem := w32.HGLOBAL(w32.GetClipboardData(format))
+	if mem == 0 {
+		return nil, fmt.Errorf("读取剪贴板失败")
+	}
+	size := globalSize(mem)
+	ptr := w32.GlobalLock(mem)
+	if ptr == nil {
+		return nil, fmt.Errorf("读取剪贴板失败")
+	}
+	defer w32.GlobalUnlock(mem)
+	data := make([]byte, size)
+	copy(data, unsafe.Slice((*byte)(ptr), size))
+	return data, nil
+}
+
+// write 清空剪贴板并写入 format 格式的数据
+func (c systemClipboard) write(format uint, data []byte) error {
+	if err := c.open(); err != nil {
+		return err
+	}
+	defer w32.CloseClipboard()
+	w32.EmptyClipboard()
+
+	mem := w32.GlobalAlloc(w32.GMEM_MOVEABLE, uint32(len(data)))
+	if mem == 0 {
+		return fmt.Errorf("分配剪贴板内存失败")
+	}
+	dst := w32.GlobalLock(mem)
+	w32.MoveMemory(dst, unsafe.Pointer(&data[0]), uint32(len(data)))
+	w32.GlobalUnlock(mem)
+
+	if w32.SetClipboardData(format, w32.HANDLE(mem)) == 0 {
+		w32.GlobalFree(mem)
+		return fmt.Errorf("写入剪贴板失败")
+	}
+	return nil
+}
+
+func (c systemClipboard) Text() (string, error) {
+	data, err := c.read(w32.CF_UNICODETEXT)
+	if err != nil {
+		return "", err
+	}
+	return decodeUTF16(data), nil
+}
+
+func (c systemClipboard) SetText(text string) error {
+	return c.write(w32.CF_UNICODETEXT, encodeUTF16(text))
+}
+
+func (c systemClipboard) Image() (image.Image, error) {
+	data, err := c.read(w32.CF_DIB)
+	if err != nil {
+		return nil, err
+	}
+	return decodeDIB(data)
+}
+
+func (c systemClipboard) SetImage(img image.Image) error {
+	return c.write(w32.CF_DIB, encodeDIB(img))
+}
+
+func (c systemClipboard) Files() ([]string, error) {
+	data, err := c.read(w32.CF_HDROP)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < dropFilesSize {
+		return nil, fmt.Errorf("文件列表数据无效")
+	}
+	offset := binary.LittleEndian.Uint32(data[0:])
+	wide := binary.LittleEndian.Uint32(data[16:]) != 0
+	if !wide || int(offset) > len(data) {
+		return nil, fmt.Errorf("不支持的文件列表格式")
+	}
+	var files []string
+	list := data[offset:]
+	for len(list) >= 2 {
+		end := 0
+		for end+1 < len(list) && (list[end] != 0 || list[end+1] != 0) {
+			end += 2
+		}
+		if end == 0 {
+			break
+		}
+		files = append(files, decodeUTF16(list[:end]))
+		list = list[min(end+2, len(list)):]
+	}
+	return files, nil
+}
+
+func (c systemClipboard) SetFiles(paths []string) error {
+	data := make([]byte, dropFilesSize)
+	binary.LittleEndian.PutUint32(data[0:], dropFilesSize) // pFiles
+	binary.LittleEndian.PutUint32(data[16:], 1)            // fWide
+	for _, p := range paths {
+		data = append(data, encodeUTF16(p)...)
+	}
+	data = append(data, 0, 0) // 列表以空字符串结尾
+	return c.write(w32.CF_HDROP, data)
+}
+
+func (systemClipboard) Format() ClipboardFormat {
+	switch {
+	case w32.IsClipboardFormatAvailable(w32.CF_HDROP):
+		return ClipboardFiles
+	case w32.IsClipboardFormatAvailable(w32.CF_DIB):
+		return ClipboardImage
+	case w32.IsClipboardFormatAvailable(w32.CF_UNICODETEXT):
+		return ClipboardText
+	}
+	return ClipboardEmpty
+}
+
+func (systemClipboard) Sequence() uint32 {
+	seq, _, _ := procGetClipboardSequenceNumber.Call()
+	return uint32(seq)
+}
+
+// globalSize 全局内存块大小
+func globalSize(mem w32.HGLOBAL) int {
+	size, _, _ := procGlobalSize.Call(uintptr(mem))
+	return int(size)
+}
+
+var procGlobalSize = syscall.NewLazyDLL("kernel32.dll").NewProc("GlobalSize")
+
+// encodeUTF16 编码为以 0 结尾的 UTF-16LE
+func encodeUTF16(s string) []byte {
+	u := syscall.StringToUTF16(s)
+	out := make([]byte, len(u)*2)
+	for i, c := range u {
+		binary.LittleEndian.PutUint16(out[i*2:], c)
+	}
+	return out
+}
+
+// decodeUTF16 解码 UTF-16LE，遇到 0 结束
+func decodeUTF16(b []byte) string {
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		c := binary.LittleEndian.Uint16(b[i:])
+		if c == 0 {
+			break
+		}
+		u = append(u, c)
+	}
+	return syscall.UTF16ToString(u)
+}
+
+// encodeDIB 编码为 32 位自下而上的 DIB
+func encodeDIB(img image.Image) []byte {
+	rgba := rgbaOf(img)
+	w, h := rgba.Rect.Dx(), rgba.Rect.Dy()
+	data := make([]byte, dibHeaderSize+w*h*dibBytesPerPix)
+	binary.LittleEndian.PutUint32(data[0:], dibHeaderSize)
+	binary.LittleEndian.PutUint32(data[4:], uint32(w))
+	binary.LittleEndian.PutUint32(data[8:], uint32(h)) // 正数表示自下而上
+	binary.LittleEndian.PutUint16(data[12:], 1)
+	binary.LittleEndian.PutUint16(data[14:], 32)
+	binary.LittleEndian.PutUint32(data[20:], uint32(w*h*dibBytesPerPix))
+
+	pix := data[dibHeaderSize:]
+	for y := 0; y < h; y++ {
+		src := rgba.Pix[rgba.PixOffset(rgba.Rect.Min.X, rgba.Rect.Min.Y+y):]
+		dst := pix[(h-1-y)*w*dibBytesPerPix:]
+		for x := 0; x < w; x++ {
+			i := x * 4
+			dst[i], dst[i+1], dst[i+2], dst[i+3] = src[i+2], src[i+1], src[i], src[i+3]
+		}
+	}
+	return data
+}
+
+// decodeDIB 解码 24/32 位未压缩（或 BI_BITFIELDS）的 DIB
+func decodeDIB(data []byte) (image.Image, error) {
+	if len(data) < dibHeaderSize {
+		return nil, fmt.Errorf("图片数据无效")
+	}
+	headerSize := int(binary.LittleEndian.Uint32(data[0:]))
+	w := int(int32(binary.LittleEndian.Uint32(data[4:])))
+	h := int(int32(binary.LittleEndian.Uint32(data[8:])))
+	bpp := int(binary.LittleEndian.Uint16(data[14:]))
+	compression := binary.LittleEndian.Uint32(data[16:])
+
+	bottomUp := h > 0
+	if !bottomUp {
+		h = -h
+	}
+	if w <= 0 || h <= 0 || (bpp != 24 && bpp != 32) || (compression != 0 && compression != biBitfields) {
+		return nil, fmt.Errorf("不支持的剪贴板图片格式（%d 位，压缩方式 %d）", bpp, compression)
+	}
+	offset := headerSize
+	if compression == biBitfields && headerSize == dibHeaderSize {
+		offset += 12 // 三个颜色掩码
+	}
+	stride := (w*bpp/8 + 3) &^ 3
+	if offset+stride*h > len(data) {
+		return nil, fmt.Errorf("图片数据不完整")
+	}
+
+	// 32 位 DIB 的 alpha 通道常常全为 0，此时视为不透明
+	opaque := bpp == 24
+	if !opaque {
+		opaque = true
+		for y := 0; y < h && opaque; y++ {
+			row := data[offset+y*stride:]
+			for x := 0; x < w; x++ {
+				if row[x*4+3] != 0 {
+					opaque = false
+					break
+				}
+			}
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	bytesPerPix := bpp / 8
+	for y := 0; y < h; y++ {
+		srcY := y
+		if bottomUp {
+			srcY = h - 1 - y
+		}
+		row := data[offset+srcY*stride:]
+		for x := 0; x < w; x++ {
+			p := row[x*bytesPerPix:]
+			a := uint8(0xff)
+			if !opaque {
+				a = p[3]
+			}
+			img.SetRGBA(x, y, color.RGBA{R: p[2], G: p[1], B: p[0], A: a})
+		}
+	}
+	return img, nil
+}
@@ -0,0 +1,193 @@
+package sdk
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/gonutz/wui/v2"
+
+	"github.com/package-register/gui/imaging"
+)
+
+// CompareMode 对比视图的显示方式
+type CompareMode int
+
+const (
+	CompareSideBySide CompareMode = iota // 左右并排
+	CompareOverlay                       // 叠加，用滑块调整后图的不透明度
+	CompareDiff                          // 在前图上高亮差异
+)
+
+// 对比视图布局
+const (
+	compareToolbarHeight = 30
+	compareButtonWidth   = 60
+	compareSpacing       = 4
+	compareSliderWidth   = 160
+)
+
+// compareModes 工具栏上的模式按钮
+var compareModes = []struct {
+	name string
+	mode CompareMode
+}{
+	{"并排", CompareSideBySide},
+	{"叠加", CompareOverlay},
+	{"差异", CompareDiff},
+}
+
+// compareHighlight 差异高亮颜色
+var compareHighlight = color.NRGBA{R: 230, G: 30, B: 30, A: 255}
+
+// CompareView 两张截图的对比视图
+type CompareView struct {
+	modeBtns []*wui.Button
+	slider   *wui.Slider
+	info     *wui.Label
+	left     *ImageDisplay
+	right    *ImageDisplay
+	single   *ImageDisplay
+
+	mode      CompareMode
+	before    image.Image
+	after     image.Image
+	options   imaging.DiffOptions
+	result    imaging.DiffResult
+	highlight image.Image
+}
+
+// AddCompareView 添加对比视图：上方为模式切换与信息，下方为图片
+func (t *TabContext) AddCompareView(x, y, w, h int) *CompareView {
+	v := &CompareView{}
+
+	bx := x
+	for _, m := range compareModes {
+		mode := m.mode
+		btn := t.AddButton(m.name, bx, y, compareButtonWidth, compareToolbarHeight, func() { v.SetMode(mode) })
+		v.modeBtns = append(v.modeBtns, btn)
+		bx += compareButtonWidth + compareSpacing
+	}
+
+	v.slider = wui.NewSlider()
+	v.slider.SetBounds(bx, y, compareSliderWidth, compareToolbarHeight)
+	v.slider.SetMinMax(0, 100)
+	v.slider.SetCursorPosition(50)
+	v.slider.SetOnChange(func(int) { v.updateOverlay() })
+	t.panel.Add(v.slider)
+	t.SetAccessible(v.slider, "后图不透明度", "叠加模式下后图的不透明度")
+	bx += compareSliderWidth + compareSpacing
+
+	v.info = t.AddLabel("", bx, y+6, x+w-bx, compareToolbarHeight-6)
+
+	iy := y + compareToolbarHeight + compareSpacing
+	ih := h - compareToolbarHeight - compareSpacing
+	half := (w - compareSpacing) / 2
+	v.left = t.AddImage(x, iy, half, ih)
+	v.right = t.AddImage(x+half+compareSpacing, iy, w-half-compareSpacing, ih)
+	v.single = t.AddImage(x, iy, w, ih)
+
+	v.SetMode(CompareSideBySide)
+	return v
+}
+
+// SetDiffOptions 设置差异检测参数，已有图片时重新比较
+func (v *CompareView) SetDiffOptions(opts imaging.DiffOptions) {
+	v.options = opts
+	v.compare()
+}
+
+// SetImages 设置前后两张图片并重新比较
+func (v *CompareView) SetImages(before, after image.Image) {
+	v.before, v.after = before, after
+	v.compare()
+}
+
+// SetBefore 设置前图
+func (v *CompareView) SetBefore(img image.Image) {
+	v.SetImages(img, v.after)
+}
+
+// SetAfter 设置后图
+func (v *CompareView) SetAfter(img image.Image) {
+	v.SetImages(v.before, img)
+}
+
+// Result 最近一次比较的结果
+func (v *CompareView) Result() imaging.DiffResult {
+	return v.result
+}
+
+// Mode 当前显示方式
+func (v *CompareView) Mode() CompareMode {
+	return v.mode
+}
+
+// SetMode 切换显示方式
+func (v *CompareView) SetMode(mode CompareMode) {
+	v.mode = mode
+	for i, btn := range v.modeBtns {
+		name := compareModes[i].name
+		if compareModes[i].mode == mode {
+			name = "[" + name + "]"
+		}
+		btn.SetText(name)
+	}
+
+	sideBySide := mode == CompareSideBySide
+	v.left.paintBox.SetVisible(sideBySide)
+	v.right.paintBox.SetVisible(sideBySide)
+	v.single.paintBox.SetVisible(!sideBySide)
+	v.slider.SetVisible(mode == CompareOverlay)
+	v.refresh()
+}
+
+// compare 两张图都在时计算差异
+func (v *CompareView) compare() {
+	v.result, v.highlight = imaging.DiffResult{}, nil
+	if v.before != nil && v.after != nil {
+		v.result = imaging.Diff(v.before, v.after, v.options)
+		v.highlight = imaging.HighlightDiff(v.before, v.result, compareHighlight)
+	}
+	v.refresh()
+}
+
+// refresh 按当前模式更新图片与信息
+func (v *CompareView) refresh() {
+	switch v.mode {
+	case CompareSideBySide:
+		v.left.SetImage(v.before)
+		v.right.SetImage(v.after)
+	case CompareOverlay:
+		v.updateOverlay()
+	case CompareDiff:
+		v.single.SetImage(v.highlight)
+	}
+
+	switch {
+	case v.before == nil && v.after == nil:
+		v.info.SetText("请设置前后两张图片")
+	case v.before == nil:
+		v.info.SetText("缺少前图")
+	case v.after == nil:
+		v.info.SetText("缺少后图")
+	default:
+		v.info.SetText(fmt.Sprintf("相似度 %.2f%%  变化像素 %d  区域 %d",
+			v.result.Similarity*100, v.result.Changed, len(v.result.Regions)))
+	}
+}
+
+// updateOverlay 按滑块位置混合前后两图
+func (v *CompareView) updateOverlay() {
+	if v.mode != CompareOverlay {
+		return
+	}
+	switch {
+	case v.before != nil && v.after != nil:
+		v.single.SetImage(imaging.Mix(v.before, v.after, float64(v.slider.CursorPosition())/100))
+	case v.before != nil:
+		v.single.SetImage(v.before)
+	default:
+		v.single.SetImage(v.after)
+	}
+}
@@ -0,0 +1,249 @@
+package sdk
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"syscall"
+	"unicode/utf16"
+
+	w32 "github.com/gonutz/w32/v2"
+	"github.com/gonutz/wui/v2"
+)
+
+// contextTarget 设置了右键菜单的控件
+type contextTarget struct {
+	tab     *TabContext
+	control wui.Control
+	build   func(x, y int) *Menu
+}
+
+// SetContextMenu 为控件设置右键菜单，也可用 Shift+F10 或菜单键打开
+func (t *TabContext) SetContextMenu(c wui.Control, menu *Menu) {
+	t.SetContextMenuFunc(c, func(int, int) *Menu { return menu })
+}
+
+// SetContextMenuFunc 每次打开右键菜单时调用 build 生成菜单，x、y 为控件的客户区坐标；
+// build 返回 nil 时不显示菜单（文本框等控件显示自带的菜单）
+func (t *TabContext) SetContextMenuFunc(c wui.Control, build func(x, y int) *Menu) {
+	app := t.app
+	for _, target := range app.contextMenus {
+		if target.control == c {
+			target.tab, target.build = t, build
+			return
+		}
+	}
+	app.contextMenus = append(app.contextMenus, &contextTarget{tab: t, control: c, build: build})
+	if app.contextProc != 0 {
+		app.subclassContextTarget(c)
+	}
+}
+
+// setupContextMenus 窗口显示后接管右键菜单消息，同时处理后台 goroutine 转交的 wmInvoke
+// 与主窗口子控件的主题取色
+//
+// 文本框等控件自行处理 WM_CONTEXTMENU，需要子类化；
+// PaintBox 等静态控件的鼠标消息落到主窗口上，而 wui 处理 WM_RBUTTONUP 后不再交给
+// DefWindowProc，主窗口收不到 WM_CONTEXTMENU，因此松开右键时补发一条再按位置命中控件。
+func (app *App) setupContextMenus() {
+	app.contextProc = syscall.NewCallback(func(window w32.HWND, msg uint32, wParam, lParam, subclassID, refData uintptr) uintptr {
+		if msg == w32.WM_CONTEXTMENU && app.showContextMenu(wParam, lParam) {
+			return 0
+		}
+		return w32.DefSubclassProc(window, msg, wParam, lParam)
+	})
+	for _, target := range app.contextMenus {
+		app.subclassContextTarget(target.control)
+	}
+	app.window.SetOnMessage(func(window uintptr, msg uint32, wParam, lParam uintptr) (bool, uintptr) {
+		switch msg {
+		case w32.WM_RBUTTONUP:
+			sx, sy := w32.ClientToScreen(w32.HWND(window), int(int16(lParam)), int(int16(lParam>>16)))
+			w32.PostMessage(w32.HWND(window), w32.WM_CONTEXTMENU, window, uintptr(uint16(sx))|uintptr(uint16(sy))<<16)
+		case w32.WM_CONTEXTMENU:
+			if app.showContextMenu(wParam, lParam) {
+				return true, 0
+			}
+		case wmInvoke:
+			app.runInvoked()
+			return true, 0
+		case w32.WM_CTLCOLORSTATIC, w32.WM_CTLCOLOREDIT, w32.WM_CTLCOLORLISTBOX:
+			if brush := app.painter.controlColor(msg, wParam); brush != 0 {
+				return true, brush
+			}
+		}
+		return false, 0
+	})
+}
+
+func (app *App) subclassContextTarget(c wui.Control) {
+	if c.Handle() != 0 {
+		w32.SetWindowSubclass(w32.HWND(c.Handle()), app.contextProc, 0, 0)
+	}
+}
+
+// showContextMenu 处理 WM_CONTEXTMENU：source 为右键所在的窗口，lParam 为屏幕坐标，键盘打开时为 -1
+func (app *App) showContextMenu(source, lParam uintptr) bool {
+	x, y := int(int16(lParam)), int(int16(lParam>>16))
+	keyboard := x == -1 && y == -1
+	target := app.contextTargetAt(source, x, y, keyboard)
+	if target == nil {
+		return false
+	}
+	hwnd := w32.HWND(target.control.Handle())
+	if keyboard {
+		x, y = w32.ClientToScreen(hwnd, contextMenuKeyboardOffset, contextMenuKeyboardOffset)
+	}
+	// 带边框的控件（如文本框）窗口矩形与客户区不同，EM_CHARFROMPOS 等需要客户区坐标
+	cx, cy, _ := w32.ScreenToClient(hwnd, x, y)
+	menu := target.build(cx, cy)
+	if menu == nil || len(menu.items) == 0 {
+		return false
+	}
+	app.popupMenu(menu, x, y)
+	return true
+}
+
+// contextMenuKeyboardOffset 用键盘打开时菜单相对控件客户区左上角的位置
+const contextMenuKeyboardOffset = 8
+
+// contextTargetAt 找到右键菜单所属的控件：先按消息来源窗口匹配，
+// 来源是主窗口时按屏幕位置（键盘打开时按焦点）在当前 Tab 的可见控件中命中
+func (app *App) contextTargetAt(source uintptr, x, y int, keyboard bool) *contextTarget {
+	if keyboard && app.window != nil && source == app.window.Handle() {
+		source = uintptr(w32.GetFocus())
+	}
+	var hit *contextTarget
+	for _, target := range app.contextMenus {
+		c := target.control
+		if target.tab.name != app.activeTab || c.Handle() == 0 || !wui.Visible(c) {
+			continue
+		}
+		if c.Handle() == source {
+			return target
+		}
+		if keyboard || app.window == nil || source != app.window.Handle() {
+			continue
+		}
+		rect := w32.GetWindowRect(w32.HWND(c.Handle()))
+		if x >= int(rect.Left) && x < int(rect.Right) && y >= int(rect.Top) && y < int(rect.Bottom) {
+			hit = target // 后添加的在上层
+		}
+	}
+	return hit
+}
+
+// popupMenu 在屏幕位置 (x, y) 弹出菜单并执行选中的菜单项
+func (app *App) popupMenu(m *Menu, x, y int) {
+	const (
+		tpmRightButton = 0x0002
+		tpmNoNotify    = 0x0080
+		tpmReturnCmd   = 0x0100
+	)
+	var items []*MenuItem
+	var build func(m *Menu, path string) w32.HMENU
+	build = func(m *Menu, path string) w32.HMENU {
+		h := w32.CreatePopupMenu()
+		for _, item := range m.items {
+			switch {
+			case item.separator:
+				w32.AppendMenu(h, w32.MF_SEPARATOR, 0, "")
+			case item.submenu != nil:
+				w32.AppendMenu(h, w32.MF_POPUP, uintptr(build(item.submenu, menuPath(path, item.title))), item.title)
+			default:
+				flags := uint(w32.MF_STRING)
+				if item.checked {
+					flags |= w32.MF_CHECKED
+				}
+				item.name = menuPath(path, item.title)
+				items = append(items, item)
+				w32.AppendMenu(h, flags, uintptr(len(items)), item.label()) // 命令 ID 从 1 开始，0 表示取消
+			}
+		}
+		return h
+	}
+	h := build(m, "")
+	defer w32.DestroyMenu(h) // 同时销毁子菜单
+
+	cmd := w32.TrackPopupMenu(h, tpmRightButton|tpmNoNotify|tpmReturnCmd, x, y, w32.HWND(app.window.Handle()), nil)
+	if cmd > 0 && cmd <= len(items) {
+		items[cmd-1].activate(app)
+	}
+}
+
+// contextMenu 图片的右键菜单：复制、另存为与缩放；标注编辑中右键交给编辑器
+func (img *ImageDisplay) contextMenu(x, y int) *Menu {
+	if img.editor != nil || img.image == nil {
+		return nil
+	}
+	m := NewMenu()
+	m.AddItem("复制(&C)", "", func() {
+		if err := img.CopyToClipboard(); err != nil {
+			log.Printf("Copy image failed: %v", err)
+		}
+	})
+	m.AddItem("另存为(&A)…", "", func() {
+		if _, err := img.SaveAs("screenshot_{time}.png"); err != nil && !errors.Is(err, ErrSaveCancelled) {
+			log.Printf("Save image failed: %v", err)
+		}
+	})
+	m.AddSeparator()
+	m.AddItem("适应窗口", "", img.Fit)
+	m.AddItem("实际大小", "", img.ActualSize)
+	return m
+}
+
+// contextMenu 聊天记录的右键菜单：复制光标处的消息或选中的文字
+func (c *ChatPanel) contextMenu(x, y int) *Menu {
+	m := NewMenu()
+	if message := c.messageAt(x, y); message != "" {
+		m.AddItem("复制消息(&M)", "", func() {
+			if err := c.app.clipboard.SetText(message); err != nil {
+				log.Printf("Copy message failed: %v", err)
+			}
+		})
+	}
+	if start, end := c.history.CursorPosition(); start != end {
+		m.AddItem("复制选中内容(&C)", "", func() {
+			w32.SendMessage(w32.HWND(c.history.Handle()), w32.WM_COPY, 0, 0)
+		})
+	}
+	m.AddItem("全选(&A)", "", c.history.SelectAll)
+	return m
+}
+
+// messageAt 聊天记录中位于客户区坐标 (x, y) 处的消息内容
+func (c *ChatPanel) messageAt(x, y int) string {
+	h := w32.HWND(c.history.Handle())
+	// EM_CHARFROMPOS 的字符位置只有低 16 位，借助所在行的起始位置还原
+	pos := w32.SendMessage(h, w32.EM_CHARFROMPOS, 0, uintptr(uint16(x))|uintptr(uint16(y))<<16)
+	line := int(pos >> 16 & 0xFFFF)
+	lineStart := int(w32.SendMessage(h, w32.EM_LINEINDEX, uintptr(line), 0))
+	index := lineStart + (int(pos&0xFFFF)-lineStart)&0xFFFF
+	return chatMessageAt(c.history.Text(), index)
+}
+
+// chatHeader 聊天记录中的消息头，如 "[12:00:00] AI:" 或 "[12:00:00] 系统:"
+var chatHeader = regexp.MustCompile(`(?m)^\[\d{2}:\d{2}:\d{2}\] [^\r\n:]+:`)
+
+// chatMessageAt 聊天记录文字中第 index 个字符（UTF-16）所在消息的内容，不含消息头
+func chatMessageAt(text string, index int) string {
+	units := utf16.Encode([]rune(text))
+	if index < 0 || index > len(units) {
+		return ""
+	}
+	offset := len(string(utf16.Decode(units[:index])))
+	headers := chatHeader.FindAllStringIndex(text, -1)
+	for i := len(headers) - 1; i >= 0; i-- {
+		if headers[i][0] > offset {
+			continue
+		}
+		end := len(text)
+		if i+1 < len(headers) {
+			end = headers[i+1][0]
+		}
+		return strings.TrimSpace(text[headers[i][1]:end])
+	}
+	return ""
+}
@@ -0,0 +1,215 @@
+package sdk
+
+import (
+	"sort"
+
+	w32 "github.com/gonutz/w32/v2"
+	"github.com/gonutz/wui/v2"
+
+	"github.com/package-register/gui/event"
+)
+
+// FocusChange 焦点变化事件的数据
+type FocusChange struct {
+	Tab     string
+	Control wui.Control
+}
+
+// canFocus 可以获得键盘焦点的控件类型
+func canFocus(c wui.Control) bool {
+	switch c.(type) {
+	case *wui.Button, *wui.CheckBox, *wui.RadioButton, *wui.ComboBox,
+		*wui.EditLine, *wui.TextEdit, *wui.IntUpDown, *wui.FloatUpDown,
+		*wui.Slider, *wui.StringList, *wui.StringTable:
+		return true
+	}
+	return false
+}
+
+// focusControl 设置键盘焦点，窗口未显示时无效
+func focusControl(c wui.Control) {
+	if c != nil && c.Handle() != 0 {
+		w32.SetFocus(w32.HWND(c.Handle()))
+	}
+}
+
+// focusables 本 Tab 中可获得焦点的控件，按添加顺序（含子面板中的控件）
+func (t *TabContext) focusables() []wui.Control {
+	var out []wui.Control
+	var walk func(children []wui.Control)
+	walk = func(children []wui.Control) {
+		for _, c := range children {
+			if p, ok := c.(*wui.Panel); ok {
+				walk(p.Children())
+			} else if canFocus(c) {
+				out = append(out, c)
+			}
+		}
+	}
+	walk(t.panel.Children())
+	return out
+}
+
+// SetTabIndex 设置控件的 Tab 键顺序，规则与 HTML 的 tabindex 相同：
+// 正数按从小到大排在最前，0（默认）按添加顺序排在其后，负数表示不参与 Tab 键导航（仍可通过 Focus 聚焦）
+func (t *TabContext) SetTabIndex(c wui.Control, index int) {
+	if t.tabIndex == nil {
+		t.tabIndex = make(map[wui.Control]int)
+	}
+	t.tabIndex[c] = index
+}
+
+// TabOrder 本 Tab 中参与 Tab 键导航的控件，按导航顺序排列
+func (t *TabContext) TabOrder() []wui.Control {
+	var order []wui.Control
+	for _, c := range t.focusables() {
+		if t.tabIndex[c] >= 0 {
+			order = append(order, c)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := t.tabIndex[order[i]], t.tabIndex[order[j]]
+		if a == 0 || b == 0 {
+			return a != 0 && b == 0
+		}
+		return a < b
+	})
+	return order
+}
+
+// SetDefaultFocus 切换到本 Tab 且此前没有焦点记录时聚焦的控件，未设置时为 Tab 键顺序中的第一个
+func (t *TabContext) SetDefaultFocus(c wui.Control) {
+	t.defaultFocus = c
+}
+
+// Focus 聚焦控件；本 Tab 未激活时先记下，切换到本 Tab 时生效
+func (t *TabContext) Focus(c wui.Control) {
+	t.lastFocus = c
+	if t.app.activeTab == t.name {
+		focusControl(c)
+	}
+}
+
+// Focused 本 Tab 中当前（或切换走之前最后）获得焦点的控件
+func (t *TabContext) Focused() wui.Control {
+	return t.lastFocus
+}
+
+// FocusNext 按 Tab 键顺序聚焦下一个控件
+func (t *TabContext) FocusNext() {
+	t.moveFocus(1)
+}
+
+// FocusPrev 按 Tab 键顺序聚焦上一个控件
+func (t *TabContext) FocusPrev() {
+	t.moveFocus(-1)
+}
+
+// moveFocus 在可见且可用的控件间循环移动焦点
+func (t *TabContext) moveFocus(step int) {
+	var order []wui.Control
+	for _, c := range t.TabOrder() {
+		if c.Handle() != 0 && wui.Visible(c) && wui.Enabled(c) {
+			order = append(order, c)
+		}
+	}
+	if len(order) == 0 {
+		return
+	}
+	focus := uintptr(w32.GetFocus())
+	next := 0
+	if step < 0 {
+		next = len(order) - 1
+	}
+	for i, c := range order {
+		if c.Handle() == focus {
+			next = ((i+step)%len(order) + len(order)) % len(order)
+			break
+		}
+	}
+	t.Focus(order[next])
+}
+
+// restoreFocus 切换到本 Tab 时恢复焦点：上次的焦点、默认焦点或第一个控件
+func (t *TabContext) restoreFocus() {
+	target := t.lastFocus
+	if target == nil {
+		target = t.defaultFocus
+	}
+	if target == nil {
+		if order := t.TabOrder(); len(order) > 0 {
+			target = order[0]
+		}
+	}
+	focusControl(target)
+}
+
+// FocusNext 在当前 Tab 中聚焦下一个控件
+func (app *App) FocusNext() {
+	if t, ok := app.tabs[app.activeTab]; ok {
+		t.FocusNext()
+	}
+}
+
+// FocusPrev 在当前 Tab 中聚焦上一个控件
+func (app *App) FocusPrev() {
+	if t, ok := app.tabs[app.activeTab]; ok {
+		t.FocusPrev()
+	}
+}
+
+// Focused 当前 Tab 中获得焦点的控件
+func (app *App) Focused() wui.Control {
+	if t, ok := app.tabs[app.activeTab]; ok {
+		return t.Focused()
+	}
+	return nil
+}
+
+// handleTabKey Tab / Shift+Tab 在当前 Tab 内导航；多行文本框允许输入制表符时交给控件
+func (app *App) handleTabKey(mods Modifiers) bool {
+	if mods != 0 && mods != ModShift {
+		return false
+	}
+	if app.cheatsheet != nil && app.cheatsheet.visible {
+		return false
+	}
+	t, ok := app.tabs[app.activeTab]
+	if !ok {
+		return false
+	}
+	if edit, ok := t.lastFocus.(*wui.TextEdit); ok && edit.WritesTabs() && edit.Handle() == uintptr(w32.GetFocus()) {
+		return false
+	}
+	if mods == ModShift {
+		t.FocusPrev()
+	} else {
+		t.FocusNext()
+	}
+	return true
+}
+
+// focusHook 线程 CBT 钩子：控件即将获得焦点时记录到所属 Tab 并发布 event.FocusChanged
+func (app *App) focusHook(code int, wParam w32.WPARAM, lParam w32.LPARAM) w32.LRESULT {
+	const hcbtSetFocus = 9
+	if code == hcbtSetFocus && wParam != 0 {
+		app.focusChanged(uintptr(wParam))
+	}
+	return w32.CallNextHookEx(app.cbtHook, code, wParam, lParam)
+}
+
+func (app *App) focusChanged(handle uintptr) {
+	for _, name := range app.tabOrder {
+		t, ok := app.tabs[name]
+		if !ok {
+			continue
+		}
+		for _, c := range t.focusables() {
+			if c.Handle() == handle {
+				t.lastFocus = c
+				app.events.Emit(event.FocusChanged, FocusChange{Tab: name, Control: c})
+				return
+			}
+		}
+	}
+}
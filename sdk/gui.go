@@ -2,6 +2,7 @@ package sdk
 
 import (
 	"log"
+	"sync"
 
 	"github.com/package-register/gui/event"
 	"github.com/package-register/gui/tray"
@@ -21,14 +22,32 @@ type TraySetupFunc func(t *TrayProxy)
 
 // App 应用程序主体
 type App struct {
-	window  *wui.Window
-	events  *event.Bus
-	tray    *tray.Tray
-	visible bool
-	font    *wui.Font
-
-	// 键盘事件追踪
-	chatInputs map[uintptr]*ChatPanel // 使用句柄追踪聊天输入框
+	window    *wui.Window
+	events    *event.Bus
+	tray      *tray.Tray
+	trayIcons *trayIcons // 托盘图标状态
+	visible   bool
+	font      *wui.Font
+
+	// 键盘事件
+	chatPanels []*ChatPanel      // 回车发送与 Ctrl+L 聚焦的聊天面板
+	shortcuts  []*Shortcut       // 窗口内快捷键
+	keymap     map[string]string // 按动作名称覆盖的键位
+	keyHook    w32.HHOOK         // 线程键盘钩子
+	cbtHook    w32.HHOOK         // 焦点变化钩子
+	cheatsheet *cheatsheet       // 快捷键列表
+
+	// 鼠标事件分发
+	mouse *mouseRouter
+
+	// 交给界面线程执行的函数
+	invokeMu    sync.Mutex
+	invokeQueue []func()
+
+	// 菜单
+	menuSetup    MenuSetupFunc
+	contextMenus []*contextTarget // 设置了右键菜单的控件
+	contextProc  uintptr          // 右键菜单子类化回调，窗口显示后创建
 
 	// 配置
 	title       string
@@ -51,7 +70,16 @@ type App struct {
 	tabs     map[string]*TabContext
 	tabBar   []*wui.Button
 	contentY int
-	theme    *Theme // 主题配置
+	theme    *Theme        // 主题配置
+	painter  *themePainter // 按主题绘制控件颜色，Run 中创建
+
+	saveDialog SaveDialogFunc // 另存为对话框
+	ocr        TextRecognizer // 默认文字识别后端
+	clipboard  *Clipboard     // 剪贴板服务
+	hotkeys    *Hotkeys       // 全局快捷键
+
+	accProps *accPropServices // 读屏软件属性服务，首次使用时创建
+	accErr   error            // 属性服务不可用的原因
 }
 
 // New 创建新的GUI应用
@@ -61,8 +89,6 @@ func New(opts ...Option) *App {
 		title:     "oAo Agent",
 		width:     600,
 		height:    400,
-		fontName:  "微软雅黑",
-		fontSize:  -14,
 		tabSetups: make(map[string]TabSetupFunc),
 		tabs:      make(map[string]*TabContext),
 		contentY:  50, // 调整以适应新的 Tab 栏高度
@@ -71,8 +97,15 @@ func New(opts ...Option) *App {
 		opt(app)
 	}
 
-	// 初始化聊天输入框追踪
-	app.chatInputs = make(map[uintptr]*ChatPanel)
+	app.registerBuiltinShortcuts()
+	app.mouse = newMouseRouter(app)
+	if app.clipboard == nil {
+		app.clipboard = NewClipboard(systemClipboard{}, app.events)
+	}
+	if app.hotkeys == nil {
+		app.hotkeys = NewHotkeys(&systemHotkeys{}, app.events)
+	}
+	app.hotkeys.dispatch = app.invoke
 
 	return app
 }
@@ -95,7 +128,7 @@ func WithTray(tooltip string, icon []byte) Option {
 	}
 }
 
-// WithFont 设置字体
+// WithFont 设置字体，默认使用主题的 DefaultFont 与 FontSize
 func WithFont(name string, size int) Option {
 	return func(a *App) {
 		a.fontName = name
@@ -133,8 +166,19 @@ func (app *App) Events() *event.Bus {
 	return app.events
 }
 
+// Tabs 已注册的Tab名称，按注册顺序
+func (app *App) Tabs() []string {
+	return append([]string(nil), app.tabOrder...)
+}
+
+// ActiveTab 当前Tab名称
+func (app *App) ActiveTab() string {
+	return app.activeTab
+}
+
 // SwitchTab 切换Tab
 func (app *App) SwitchTab(name string) {
+	app.HideShortcuts()
 	if app.activeTab == name {
 		return
 	}
@@ -145,6 +189,7 @@ func (app *App) SwitchTab(name string) {
 	// 显示新Tab
 	if next, ok := app.tabs[name]; ok {
 		next.show()
+		next.restoreFocus()
 		app.activeTab = name
 		app.updateTabBar()
 		app.events.Emit(event.TabSwitch, name)
@@ -186,6 +231,9 @@ func (app *App) IsVisible() bool {
 
 // Exit 退出应用
 func (app *App) Exit() {
+	if app.trayIcons != nil {
+		app.trayIcons.close()
+	}
 	if app.tray != nil {
 		app.tray.Quit()
 		app.tray = nil // 防止Run()末尾重复Quit
@@ -200,19 +248,26 @@ func (app *App) Run() error {
 	// 创建窗口
 	app.window = wui.NewWindow()
 	app.window.SetTitle(app.title)
-	app.window.SetInnerBounds(100, 50, app.width, app.height)
 
 	// 设置控制台显示
 	if app.hideConsole {
 		app.window.HideConsoleOnStart()
 	}
 
-	// 设置字体
-	app.initFont()
+	// 应用主题：窗口背景色与字体，控件颜色在窗口显示后设置
+	theme := app.Theme()
+	app.painter = newThemePainter(theme)
+	app.window.SetBackground(theme.Background)
+	app.initFont(theme)
 
 	// 构建Tab栏和内容
 	app.buildTabBar()
 	app.buildTabContents()
+	app.buildCheatsheet()
+
+	// 菜单栏占用窗口高度，设置菜单后再设置内部尺寸
+	app.buildMenuBar()
+	app.window.SetInnerBounds(100, 50, app.width, app.height)
 
 	// 窗口关闭行为
 	if app.trayEnabled {
@@ -225,14 +280,13 @@ func (app *App) Run() error {
 	// 初始化托盘
 	if app.trayEnabled {
 		app.tray = tray.NewTray()
-		if app.trayIcon != nil {
-			app.tray.SetIcon(app.trayIcon)
-		}
-		if app.trayTooltip != "" {
-			app.tray.SetTooltip(app.trayTooltip)
-		}
+		app.tray.SetEventBus(app.events)
+		app.tray.SetDoubleClickInterval(doubleClickTime())
+		app.tray.OnClick(app.ToggleWindow)
+		app.trayIcons = newTrayIcons(app.tray, theme, app.trayIcon, app.trayTooltip)
+		app.events.On(event.WindowShow, func(event.Event) { app.trayIcons.seen() })
 		if app.traySetup != nil {
-			app.traySetup(&TrayProxy{tray: app.tray})
+			app.traySetup(&TrayProxy{tray: app.tray, icons: app.trayIcons})
 		}
 		if err := app.tray.Start(); err != nil {
 			log.Printf("Tray start error: %v", err)
@@ -251,13 +305,30 @@ func (app *App) Run() error {
 	// 设置键盘事件处理
 	app.setupKeyboardHandler()
 
+	// 设置鼠标事件分发
+	app.setupMouseHandler()
+
 	app.events.Emit(event.AppStart, nil)
 	app.visible = true
+	app.clipboard.Watch()
 
 	// 显示窗口（阻塞直到窗口关闭）
 	err := app.window.Show()
 
+	for _, hook := range []*w32.HHOOK{&app.keyHook, &app.cbtHook} {
+		if *hook != 0 {
+			w32.UnhookWindowsHookEx(*hook)
+			*hook = 0
+		}
+	}
+	app.clipboard.Stop()
+	app.hotkeys.Close()
+	app.painter.close()
+
 	// 窗口关闭后清理托盘
+	if app.trayIcons != nil {
+		app.trayIcons.close()
+	}
 	if app.tray != nil {
 		app.tray.Quit()
 	}
@@ -326,11 +397,16 @@ func (app *App) updateTabBar() {
 	}
 }
 
-func (app *App) initFont() {
-	if app.fontName != "" {
+// initFont 设置窗口字体：WithFont 指定的字体优先，否则使用主题字体
+func (app *App) initFont(theme *Theme) {
+	name, size := app.fontName, app.fontSize
+	if name == "" {
+		name, size = theme.DefaultFont, theme.FontSize
+	}
+	if name != "" {
 		f, err := wui.NewFont(wui.FontDesc{
-			Name:   app.fontName,
-			Height: app.fontSize,
+			Name:   name,
+			Height: size,
 		})
 		if err != nil && err != wui.NoExactFontMatch {
 			log.Printf("Font error: %v, falling back", err)
@@ -341,24 +417,32 @@ func (app *App) initFont() {
 	}
 }
 
-// registerChatInput 注册聊天输入框（供 TabContext 调用）
-func (app *App) registerChatInput(input *wui.EditLine, chatPanel *ChatPanel) {
-	app.chatInputs[input.Handle()] = chatPanel
+// registerChatInput 注册聊天面板（供 TabContext 调用）
+func (app *App) registerChatInput(chatPanel *ChatPanel) {
+	app.chatPanels = append(app.chatPanels, chatPanel)
 }
 
-// setupKeyboardHandler 设置键盘事件处理
+// setupKeyboardHandler 窗口显示后在界面线程上安装键盘与焦点钩子，设置无障碍信息与右键菜单，并聚焦默认控件
+//
+// 不使用窗口的 WM_KEYDOWN：焦点在子控件上时窗口收不到按键；
+// 也不使用 wui 的加速键表与 Tab 键导航：wui 在消息循环中先行拦截 Tab 键，
+// Ctrl+Tab 无法到达，且导航会进入隐藏 Tab 中的控件。
 func (app *App) setupKeyboardHandler() {
-	// VK_RETURN = 13 (Enter 键)
-	const VK_RETURN = 13
-
-	app.window.SetOnKeyDown(func(key int) {
-		if key == VK_RETURN {
-			// 获取当前聚焦的控件句柄
-			focusedHandle := uintptr(w32.GetFocus())
-			// 检查是否是已注册的聊天输入框
-			if chatPanel, ok := app.chatInputs[focusedHandle]; ok {
-				chatPanel.SendInput()
-			}
+	app.window.SetOnShow(func() {
+		thread, _, _ := procGetCurrentThreadId.Call()
+		app.keyHook = w32.SetWindowsHookEx(w32.WH_KEYBOARD, app.keyboardHook, 0, w32.DWORD(thread))
+		if app.keyHook == 0 {
+			log.Printf("Keyboard hook install failed, shortcuts disabled")
+		}
+		app.cbtHook = w32.SetWindowsHookEx(w32.WH_CBT, app.focusHook, 0, w32.DWORD(thread))
+		if app.cbtHook == 0 {
+			log.Printf("Focus hook install failed, focus events disabled")
+		}
+		app.applyAllAccessibility()
+		app.setupContextMenus()
+		app.painter.apply(app.window)
+		if t, ok := app.tabs[app.activeTab]; ok {
+			t.restoreFocus()
 		}
 	})
 }
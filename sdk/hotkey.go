@@ -0,0 +1,243 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/package-register/gui/event"
+)
+
+// ErrHotkeyConflict 组合键已被本应用或其他程序占用
+var ErrHotkeyConflict = errors.New("快捷键已被占用")
+
+// HotkeyBackend 全局快捷键后端
+type HotkeyBackend interface {
+	// Start 开始监听，按下已注册的组合键时以其 id 调用 trigger
+	Start(trigger func(id int)) error
+	// Register 注册组合键，被其他程序占用时返回 ErrHotkeyConflict
+	Register(id int, acc Accelerator) error
+	Unregister(id int) error
+	// Close 注销全部组合键并停止监听
+	Close()
+}
+
+// hotkeyBinding 已注册的全局快捷键
+type hotkeyBinding struct {
+	id      int
+	acc     Accelerator
+	handler func()
+}
+
+// Hotkeys 全局快捷键服务：应用不在前台时也能响应，按下时发布 event.HotkeyPressed
+type Hotkeys struct {
+	backend  HotkeyBackend
+	events   *event.Bus
+	dispatch func(func()) // 执行处理函数，应用中转交界面线程；为 nil 时直接执行
+
+	mu       sync.Mutex
+	started  bool
+	nextID   int
+	bindings map[Accelerator]*hotkeyBinding
+}
+
+// NewHotkeys 创建全局快捷键服务，events 可为 nil
+func NewHotkeys(backend HotkeyBackend, events *event.Bus) *Hotkeys {
+	return &Hotkeys{backend: backend, events: events, nextID: 1, bindings: make(map[Accelerator]*hotkeyBinding)}
+}
+
+// WithHotkeyBackend 替换应用的全局快捷键后端（如测试中使用 NewFakeHotkeys）
+func WithHotkeyBackend(backend HotkeyBackend) Option {
+	return func(a *App) { a.hotkeys = NewHotkeys(backend, a.events) }
+}
+
+// Hotkeys 应用的全局快捷键服务
+func (app *App) Hotkeys() *Hotkeys {
+	return app.hotkeys
+}
+
+// RegisterHotkey 注册全局快捷键，如 app.RegisterHotkey("Ctrl+Shift+S", handler)
+//
+// 常用动作可直接绑定：app.ToggleWindow 切换窗口，TabContext.ScreenshotAction 截图。
+func (app *App) RegisterHotkey(accelerator string, handler func()) error {
+	return app.hotkeys.Register(accelerator, handler)
+}
+
+// UnregisterHotkey 注销全局快捷键
+func (app *App) UnregisterHotkey(accelerator string) error {
+	return app.hotkeys.Unregister(accelerator)
+}
+
+// Register 注册全局快捷键；与已注册的组合键相同或被其他程序占用时返回 ErrHotkeyConflict
+func (h *Hotkeys) Register(accelerator string, handler func()) error {
+	acc, err := ParseAccelerator(accelerator)
+	if err != nil {
+		return err
+	}
+	if acc.Mods == 0 {
+		return fmt.Errorf("全局快捷键 %s 至少需要一个修饰键", acc)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.bindings[acc]; ok {
+		return fmt.Errorf("%s: %w", acc, ErrHotkeyConflict)
+	}
+	if !h.started {
+		if err := h.backend.Start(h.trigger); err != nil {
+			return fmt.Errorf("启动全局快捷键失败: %w", err)
+		}
+		h.started = true
+	}
+	id := h.nextID
+	if err := h.backend.Register(id, acc); err != nil {
+		return fmt.Errorf("%s: %w", acc, err)
+	}
+	h.nextID++
+	h.bindings[acc] = &hotkeyBinding{id: id, acc: acc, handler: handler}
+	return nil
+}
+
+// Unregister 注销全局快捷键
+func (h *Hotkeys) Unregister(accelerator string) error {
+	acc, err := ParseAccelerator(accelerator)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	b, ok := h.bindings[acc]
+	if !ok {
+		return fmt.Errorf("快捷键 %s 未注册", acc)
+	}
+	delete(h.bindings, acc)
+	return h.backend.Unregister(b.id)
+}
+
+// Registered 已注册的组合键，按名称排序
+func (h *Hotkeys) Registered() []Accelerator {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]Accelerator, 0, len(h.bindings))
+	for acc := range h.bindings {
+		out = append(out, acc)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].String() < out[j].String() })
+	return out
+}
+
+// Close 注销全部快捷键并停止监听
+func (h *Hotkeys) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.started {
+		h.backend.Close()
+		h.started = false
+	}
+	h.bindings = make(map[Accelerator]*hotkeyBinding)
+}
+
+// trigger 后端回调：发布事件后执行处理函数
+//
+// 系统后端在各自的 goroutine 中回调，处理函数（如 ToggleWindow、截图）会操作窗口，经 dispatch 转交界面线程。
+func (h *Hotkeys) trigger(id int) {
+	h.mu.Lock()
+	var binding *hotkeyBinding
+	for _, b := range h.bindings {
+		if b.id == id {
+			binding = b
+			break
+		}
+	}
+	h.mu.Unlock()
+	if binding == nil {
+		return
+	}
+	if h.events != nil {
+		h.events.Emit(event.HotkeyPressed, binding.acc)
+	}
+	if binding.handler == nil {
+		return
+	}
+	if h.dispatch != nil {
+		h.dispatch(binding.handler)
+	} else {
+		binding.handler()
+	}
+}
+
+// ScreenshotAction 返回执行截图流程的函数，可绑定到全局快捷键或托盘菜单
+func (t *TabContext) ScreenshotAction(hideWindow bool, callback ScreenshotCallback, opts ...ScreenshotOption) func() {
+	return func() { t.takeScreenshot(hideWindow, callback, opts...) }
+}
+
+// FakeHotkeys 进程内的全局快捷键后端，用于测试
+type FakeHotkeys struct {
+	mu       sync.Mutex
+	trigger  func(id int)
+	ids      map[Accelerator]int
+	reserved map[Accelerator]bool
+}
+
+// NewFakeHotkeys 创建进程内快捷键后端，reserved 模拟已被其他程序占用的组合键
+func NewFakeHotkeys(reserved ...string) *FakeHotkeys {
+	f := &FakeHotkeys{ids: make(map[Accelerator]int), reserved: make(map[Accelerator]bool)}
+	for _, s := range reserved {
+		f.reserved[MustParseAccelerator(s)] = true
+	}
+	return f
+}
+
+// Press 模拟按下组合键，返回是否有已注册的快捷键响应
+func (f *FakeHotkeys) Press(accelerator string) bool {
+	acc, err := ParseAccelerator(accelerator)
+	if err != nil {
+		return false
+	}
+	f.mu.Lock()
+	id, ok := f.ids[acc]
+	trigger := f.trigger
+	f.mu.Unlock()
+	if !ok || trigger == nil {
+		return false
+	}
+	trigger(id)
+	return true
+}
+
+func (f *FakeHotkeys) Start(trigger func(id int)) error {
+	f.mu.Lock()
+	f.trigger = trigger
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *FakeHotkeys) Register(id int, acc Accelerator) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.ids[acc]; ok || f.reserved[acc] {
+		return ErrHotkeyConflict
+	}
+	f.ids[acc] = id
+	return nil
+}
+
+func (f *FakeHotkeys) Unregister(id int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for acc, existing := range f.ids {
+		if existing == id {
+			delete(f.ids, acc)
+			return nil
+		}
+	}
+	return fmt.Errorf("快捷键 %d 未注册", id)
+}
+
+func (f *FakeHotkeys) Close() {
+	f.mu.Lock()
+	f.trigger = nil
+	f.ids = make(map[Accelerator]int)
+	f.mu.Unlock()
+}
@@ -0,0 +1,141 @@
+package sdk
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"syscall"
+
+	w32 "github.com/gonutz/w32/v2"
+)
+
+var (
+	procRegisterHotKey     = syscall.NewLazyDLL("user32.dll").NewProc("RegisterHotKey")
+	procUnregisterHotKey   = syscall.NewLazyDLL("user32.dll").NewProc("UnregisterHotKey")
+	procPostThreadMessage  = syscall.NewLazyDLL("user32.dll").NewProc("PostThreadMessageW")
+	procGetCurrentThreadId = syscall.NewLazyDLL("kernel32.dll").NewProc("GetCurrentThreadId")
+)
+
+const (
+	modNoRepeat                  = 0x4000 // MOD_NOREPEAT：按住不放时不重复触发
+	errorHotkeyAlreadyRegistered = 1409
+	wmHotkeyRequest              = w32.WM_USER + 1
+)
+
+// hotkeyRequest 交给监听线程执行的注册/注销请求
+type hotkeyRequest struct {
+	register bool
+	id       int
+	acc      Accelerator
+	result   chan error
+}
+
+// systemHotkeys Windows 全局快捷键
+//
+// RegisterHotKey 注册的快捷键消息只投递到注册它的线程，
+// 因此注册、注销与消息循环都在同一个固定的线程上进行。
+type systemHotkeys struct {
+	mu       sync.Mutex
+	thread   uintptr
+	requests chan hotkeyRequest
+	done     chan struct{}
+}
+
+func (s *systemHotkeys) Start(trigger func(id int)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		return nil
+	}
+	s.requests = make(chan hotkeyRequest)
+	s.done = make(chan struct{})
+	ready := make(chan uintptr)
+	go s.loop(trigger, ready, s.requests, s.done)
+	s.thread = <-ready
+	return nil
+}
+
+func (s *systemHotkeys) loop(trigger func(id int), ready chan<- uintptr, requests <-chan hotkeyRequest, done chan<- struct{}) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	defer close(done)
+
+	// 先创建线程的消息队列，之后 PostThreadMessage 才能成功
+	var msg w32.MSG
+	w32.PeekMessage(&msg, 0, 0, 0, w32.PM_NOREMOVE)
+	thread, _, _ := procGetCurrentThreadId.Call()
+	ready <- thread
+
+	registered := make(map[int]bool)
+	defer func() {
+		for id := range registered {
+			procUnregisterHotKey.Call(0, uintptr(id))
+		}
+	}()
+
+	for w32.GetMessage(&msg, 0, 0, 0) > 0 {
+		switch msg.Message {
+		case w32.WM_HOTKEY:
+			go trigger(int(msg.WParam))
+		case wmHotkeyRequest:
+			req := <-requests
+			req.result <- applyHotkeyRequest(req, registered)
+		}
+	}
+}
+
+// applyHotkeyRequest 在监听线程上执行请求
+func applyHotkeyRequest(req hotkeyRequest, registered map[int]bool) error {
+	if !req.register {
+		delete(registered, req.id)
+		if ok, _, _ := procUnregisterHotKey.Call(0, uintptr(req.id)); ok == 0 {
+			return fmt.Errorf("注销快捷键失败")
+		}
+		return nil
+	}
+	ok, _, err := procRegisterHotKey.Call(0, uintptr(req.id), uintptr(req.acc.Mods)|modNoRepeat, uintptr(req.acc.Key))
+	if ok == 0 {
+		if errno, isErrno := err.(syscall.Errno); isErrno && errno == errorHotkeyAlreadyRegistered {
+			return ErrHotkeyConflict
+		}
+		return fmt.Errorf("注册快捷键失败: %v", err)
+	}
+	registered[req.id] = true
+	return nil
+}
+
+// send 唤醒监听线程并等待请求完成
+func (s *systemHotkeys) send(req hotkeyRequest) error {
+	s.mu.Lock()
+	thread, requests := s.thread, s.requests
+	s.mu.Unlock()
+	if requests == nil {
+		return fmt.Errorf("全局快捷键未启动")
+	}
+	req.result = make(chan error, 1)
+	if ok, _, err := procPostThreadMessage.Call(thread, wmHotkeyRequest, 0, 0); ok == 0 {
+		return fmt.Errorf("发送快捷键请求失败: %v", err)
+	}
+	requests <- req
+	return <-req.result
+}
+
+func (s *systemHotkeys) Register(id int, acc Accelerator) error {
+	return s.send(hotkeyRequest{register: true, id: id, acc: acc})
+}
+
+func (s *systemHotkeys) Unregister(id int) error {
+	return s.send(hotkeyRequest{id: id})
+}
+
+func (s *systemHotkeys) Close() {
+	s.mu.Lock()
+	thread, done := s.thread, s.done
+	s.requests, s.done = nil, nil
+	s.mu.Unlock()
+	if done == nil {
+		return
+	}
+	procPostThreadMessage.Call(thread, w32.WM_QUIT, 0, 0)
+	<-done
+}
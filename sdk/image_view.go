@@ -0,0 +1,293 @@
+package sdk
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"unsafe"
+
+	w32 "github.com/gonutz/w32/v2"
+	"github.com/gonutz/wui/v2"
+	"github.com/package-register/gui/imaging"
+)
+
+// 图片查看参数
+const (
+	zoomStep      = 1.25 // 滚轮每格的缩放倍数
+	dragThreshold = 3    // 移动超过此像素数才算拖拽，否则算单击
+)
+
+// noMouseButton 没有按下用于平移的按键
+const noMouseButton wui.MouseButton = -1
+
+// imageBackground 图片之外区域的背景色
+var imageBackground = color.RGBA{240, 240, 240, 255}
+
+// PixelInfo 光标下的像素
+type PixelInfo struct {
+	Point  image.Point // 图片坐标
+	Color  color.RGBA
+	Inside bool // 光标是否在图片内
+}
+
+// Zoom 当前缩放比例
+func (img *ImageDisplay) Zoom() float64 {
+	return img.view.Scale()
+}
+
+// ZoomIn 以显示区域中心放大
+func (img *ImageDisplay) ZoomIn() {
+	img.zoomAt(float64(img.width)/2, float64(img.height)/2, zoomStep)
+}
+
+// ZoomOut 以显示区域中心缩小
+func (img *ImageDisplay) ZoomOut() {
+	img.zoomAt(float64(img.width)/2, float64(img.height)/2, 1/zoomStep)
+}
+
+// Fit 缩放到完整显示
+func (img *ImageDisplay) Fit() {
+	img.view.Fit()
+	img.invalidate()
+}
+
+// ActualSize 以 1:1 显示
+func (img *ImageDisplay) ActualSize() {
+	img.view.ActualSize()
+	img.invalidate()
+}
+
+// ToggleFit 在适应与 1:1 之间切换
+func (img *ImageDisplay) ToggleFit() {
+	img.view.ToggleFit()
+	img.invalidate()
+}
+
+// SetFilter 设置缩放采样方式，默认缩小时用 Catmull-Rom、放大时用最近邻
+func (img *ImageDisplay) SetFilter(filter imaging.Filter) {
+	img.renderer.Filter = filter
+	img.invalidate()
+}
+
+// SetOnPixel 订阅光标下像素的变化
+func (img *ImageDisplay) SetOnPixel(onPixel func(PixelInfo)) {
+	img.onPixel = onPixel
+}
+
+// SetPixelReadout 是否在图片左下角显示光标处的坐标与颜色，默认显示
+func (img *ImageDisplay) SetPixelReadout(show bool) {
+	img.hidePixel = !show
+	img.paintBox.Paint()
+}
+
+// Pixel 光标下的像素
+func (img *ImageDisplay) Pixel() PixelInfo {
+	return img.pixel
+}
+
+func (img *ImageDisplay) zoomAt(x, y, factor float64) {
+	if img.image == nil {
+		return
+	}
+	img.view.ZoomAt(x, y, factor)
+	img.invalidate()
+}
+
+// invalidate 视图或图片变化后重新渲染
+func (img *ImageDisplay) invalidate() {
+	img.dirty = true
+	img.updatePixel()
+	img.paintBox.Paint()
+}
+
+// paint 绘制图片、标注预览与像素信息
+func (img *ImageDisplay) paint(canvas *wui.Canvas) {
+	if img.image == nil {
+		// 没有图片时显示占位符
+		canvas.FillRect(0, 0, img.width, img.height, wui.RGB(200, 200, 200))
+		canvas.TextRect(0, 0, img.width, img.height, "暂无图片", wui.RGB(0, 0, 0))
+		return
+	}
+
+	if img.buffer == nil {
+		img.buffer = image.NewRGBA(image.Rect(0, 0, img.width, img.height))
+		img.dirty = true
+	}
+	if img.dirty {
+		img.renderer.Render(img.buffer, img.view, img.rgba, imageBackground)
+		img.dirty = false
+	}
+	blitRGBA(canvas, img.buffer, &img.bits)
+
+	// 缩小显示时绘制边框
+	if img.view.Scale() < 1 {
+		r := img.view.ImageRect()
+		canvas.DrawRect(r.Min.X-1, r.Min.Y-1, r.Dx()+2, r.Dy()+2, wui.RGB(100, 100, 100))
+	}
+
+	// 标注编辑中的预览
+	if img.editor != nil {
+		img.editor.paintPreview(canvas)
+	}
+
+	if img.isHover && !img.hidePixel {
+		img.paintPixelReadout(canvas)
+	}
+}
+
+// paintPixelReadout 左下角显示坐标、颜色与缩放比例
+func (img *ImageDisplay) paintPixelReadout(canvas *wui.Canvas) {
+	zoom := fmt.Sprintf("%.0f%%", img.view.Scale()*100)
+	text := zoom
+	if img.pixel.Inside {
+		c := img.pixel.Color
+		text = fmt.Sprintf("%d, %d  #%02X%02X%02X  %s", img.pixel.Point.X, img.pixel.Point.Y, c.R, c.G, c.B, zoom)
+	}
+	tw, th := canvas.TextExtent(text)
+	const pad = 4
+	y := img.height - th - pad*2
+	canvas.FillRect(0, y, tw+pad*3+th, th+pad*2, wui.RGB(40, 40, 40))
+	x := pad
+	if img.pixel.Inside {
+		c := img.pixel.Color
+		canvas.FillRect(x, y+pad, th, th, wui.RGB(c.R, c.G, c.B))
+		canvas.DrawRect(x, y+pad, th, th, wui.RGB(255, 255, 255))
+		x += th + pad
+	}
+	canvas.TextOut(x, y+pad, text, wui.RGB(255, 255, 255))
+}
+
+// updatePixel 按最近的光标位置更新像素信息
+func (img *ImageDisplay) updatePixel() {
+	info := PixelInfo{}
+	if img.image != nil && img.isHover {
+		p, ok := img.view.ToImage(img.mouseX, img.mouseY)
+		if ok {
+			p = p.Add(img.rgba.Rect.Min)
+			info = PixelInfo{Point: p, Color: img.rgba.RGBAAt(p.X, p.Y), Inside: true}
+		}
+	}
+	if info != img.pixel {
+		img.pixel = info
+		if img.onPixel != nil {
+			img.onPixel(info)
+		}
+	}
+}
+
+// mouseHandler 滚轮缩放、拖拽平移（左键，标注编辑时为中键）、单击与像素信息
+func (img *ImageDisplay) mouseHandler() MouseHandler {
+	return MouseHandler{
+		OnDown: func(button wui.MouseButton, x, y int) {
+			img.mouseX, img.mouseY = x, y
+			img.downX, img.downY = x, y
+			img.dragged = false
+			img.panButton = noMouseButton
+			if button == wui.MouseButtonMiddle || (button == wui.MouseButtonLeft && img.editor == nil) {
+				img.panButton = button
+			}
+			if img.editor != nil && button != wui.MouseButtonMiddle {
+				img.editor.mouseDown(button, x, y)
+			}
+		},
+		OnMove: func(x, y int) {
+			dx, dy := x-img.mouseX, y-img.mouseY
+			img.mouseX, img.mouseY = x, y
+			img.isHover = true
+			if img.panButton != noMouseButton && img.image != nil {
+				if !img.dragged && (abs(x-img.downX) > dragThreshold || abs(y-img.downY) > dragThreshold) {
+					img.dragged = true
+					dx, dy = x-img.downX, y-img.downY
+				}
+				if img.dragged {
+					img.view.Pan(float64(dx), float64(dy))
+					img.invalidate()
+					return
+				}
+			}
+			if img.editor != nil {
+				img.editor.mouseMove(x, y)
+			}
+			img.updatePixel()
+			if img.image != nil && !img.hidePixel {
+				img.paintBox.Paint()
+			}
+		},
+		OnUp: func(button wui.MouseButton, x, y int) {
+			panning := img.panButton == button
+			if panning {
+				img.panButton = noMouseButton
+			}
+			if img.editor != nil && button != wui.MouseButtonMiddle {
+				img.editor.mouseUp(button, x, y)
+				return
+			}
+			if panning && img.dragged {
+				return
+			}
+			if button == wui.MouseButtonLeft && img.image != nil && img.onClick != nil {
+				img.onClick()
+			}
+		},
+		OnWheel: func(x, y int, delta float64) {
+			img.zoomAt(float64(x), float64(y), math.Pow(zoomStep, delta))
+		},
+		OnLeave: func() {
+			img.isHover = false
+			img.updatePixel()
+			img.paintBox.Paint()
+		},
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// rgbaOf 取得图片的 RGBA 像素，截图直接复用底层数据
+func rgbaOf(img image.Image) *image.RGBA {
+	switch v := img.(type) {
+	case *image.RGBA:
+		return v
+	case *Screenshot:
+		return v.RGBA
+	}
+	rgba := image.NewRGBA(img.Bounds())
+	draw.Draw(rgba, rgba.Rect, img, rgba.Rect.Min, draw.Src)
+	return rgba
+}
+
+// blitRGBA 将 RGBA 缓冲直接绘制到画布左上角，bits 为复用的 BGRA 缓冲
+//
+// 不经过 wui.NewImage，避免每次重绘都创建无法释放的位图。
+func blitRGBA(canvas *wui.Canvas, src *image.RGBA, bits *[]byte) {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	if len(*bits) != w*h*4 {
+		*bits = make([]byte, w*h*4)
+	}
+	dst := *bits
+	for y := 0; y < h; y++ {
+		row := src.Pix[y*src.Stride : y*src.Stride+w*4]
+		out := dst[y*w*4:]
+		for i := 0; i < len(row); i += 4 {
+			out[i], out[i+1], out[i+2], out[i+3] = row[i+2], row[i+1], row[i], row[i+3]
+		}
+	}
+
+	var info w32.BITMAPINFO
+	info.BmiHeader.BiSize = uint32(unsafe.Sizeof(info.BmiHeader))
+	info.BmiHeader.BiWidth = int32(w)
+	info.BmiHeader.BiHeight = -int32(h) // 自上而下
+	info.BmiHeader.BiPlanes = 1
+	info.BmiHeader.BiBitCount = 32
+	info.BmiHeader.BiCompression = w32.BI_RGB
+	w32.SetDIBitsToDevice(w32.HDC(canvas.Handle()), 0, 0, w, h, 0, 0, 0, uint(h), dst, &info, w32.DIB_RGB_COLORS)
+}
@@ -0,0 +1,46 @@
+package sdk
+
+import (
+	w32 "github.com/gonutz/w32/v2"
+)
+
+// wmInvoke 通知主窗口执行排队函数的消息
+const wmInvoke = w32.WM_APP + 1
+
+// invoke 在界面线程上执行 fn 并等待其完成
+//
+// wui 的窗口与控件只能在创建它们的线程上使用（模态窗口还会修改 wui 全局的窗口栈），
+// 后台 goroutine 需要弹出窗口时通过主窗口的消息循环转交。已在界面线程上或主窗口尚未显示时直接执行。
+func (app *App) invoke(fn func()) {
+	if app.window == nil || app.window.Handle() == 0 {
+		fn()
+		return
+	}
+	hwnd := w32.HWND(app.window.Handle())
+	uiThread, _ := w32.GetWindowThreadProcessId(hwnd)
+	if current, _, _ := procGetCurrentThreadId.Call(); uintptr(uiThread) == current {
+		fn()
+		return
+	}
+
+	done := make(chan struct{})
+	app.invokeMu.Lock()
+	app.invokeQueue = append(app.invokeQueue, func() {
+		defer close(done)
+		fn()
+	})
+	app.invokeMu.Unlock()
+	w32.PostMessage(hwnd, wmInvoke, 0, 0)
+	<-done
+}
+
+// runInvoked 在界面线程上执行排队的函数
+func (app *App) runInvoked() {
+	app.invokeMu.Lock()
+	queue := app.invokeQueue
+	app.invokeQueue = nil
+	app.invokeMu.Unlock()
+	for _, fn := range queue {
+		fn()
+	}
+}
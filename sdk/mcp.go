@@ -0,0 +1,141 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"time"
+
+	"trpc.group/trpc-go/trpc-agent-go/tool"
+	"trpc.group/trpc-go/trpc-agent-go/tool/mcp"
+)
+
+// MCPTransport MCP 服务器传输方式
+type MCPTransport string
+
+const (
+	MCPStdio      MCPTransport = "stdio"      // 启动子进程，通过标准输入输出通信
+	MCPStreamable MCPTransport = "streamable" // 本机 streamable HTTP
+)
+
+// defaultMCPTimeout MCP 连接与工具发现的默认超时
+const defaultMCPTimeout = 10 * time.Second
+
+// MCPServerConfig MCP 服务器配置
+type MCPServerConfig struct {
+	Name      string       // 服务器名称，同时作为工具名前缀避免冲突
+	Transport MCPTransport // 传输方式
+
+	// stdio 配置
+	Command string
+	Args    []string
+
+	// streamable HTTP 配置（仅允许本机地址）
+	URL     string
+	Headers map[string]string
+
+	// AllowTools 允许暴露给助手的工具名，为空表示全部允许
+	AllowTools []string
+
+	Timeout time.Duration
+}
+
+// MCPServerStatus MCP 服务器连接状态
+type MCPServerStatus struct {
+	Name  string
+	Tools []string // 已发现并允许的工具
+	Err   error    // 连接失败原因
+}
+
+// validate 校验配置
+func (c MCPServerConfig) validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("MCP 服务器缺少名称")
+	}
+	switch c.Transport {
+	case MCPStdio:
+		if c.Command == "" {
+			return fmt.Errorf("MCP 服务器 %s 缺少启动命令", c.Name)
+		}
+	case MCPStreamable:
+		u, err := url.Parse(c.URL)
+		if err != nil {
+			return fmt.Errorf("MCP 服务器 %s 地址无效: %w", c.Name, err)
+		}
+		if !isLoopbackHost(u.Hostname()) {
+			return fmt.Errorf("MCP 服务器 %s 只允许本机地址: %s", c.Name, c.URL)
+		}
+	default:
+		return fmt.Errorf("MCP 服务器 %s 传输方式不支持: %s", c.Name, c.Transport)
+	}
+	return nil
+}
+
+// isLoopbackHost 判断主机名是否为本机回环地址
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// connectMCPServer 连接 MCP 服务器并发现工具
+func connectMCPServer(cfg MCPServerConfig) (tool.ToolSet, MCPServerStatus) {
+	status := MCPServerStatus{Name: cfg.Name}
+	if err := cfg.validate(); err != nil {
+		status.Err = err
+		return nil, status
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultMCPTimeout
+	}
+
+	var opts []mcp.ToolSetOption
+	opts = append(opts, mcp.WithName(cfg.Name))
+	if len(cfg.AllowTools) > 0 {
+		opts = append(opts, mcp.WithToolFilterFunc(tool.NewIncludeToolNamesFilter(cfg.AllowTools...)))
+	}
+
+	toolSet := mcp.NewMCPToolSet(mcp.ConnectionConfig{
+		Transport: string(cfg.Transport),
+		Command:   cfg.Command,
+		Args:      cfg.Args,
+		ServerURL: cfg.URL,
+		Headers:   cfg.Headers,
+		Timeout:   timeout,
+	}, opts...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := toolSet.Init(ctx); err != nil {
+		toolSet.Close()
+		status.Err = err
+		return nil, status
+	}
+
+	for _, t := range toolSet.Tools(ctx) {
+		status.Tools = append(status.Tools, t.Declaration().Name)
+	}
+	return toolSet, status
+}
+
+// connectMCPServers 连接所有配置的 MCP 服务器，失败的服务器会被跳过
+func connectMCPServers(configs []MCPServerConfig) ([]tool.ToolSet, []MCPServerStatus) {
+	var toolSets []tool.ToolSet
+	var statuses []MCPServerStatus
+	for _, cfg := range configs {
+		toolSet, status := connectMCPServer(cfg)
+		if status.Err != nil {
+			log.Printf("MCP server %s unavailable: %v", cfg.Name, status.Err)
+		} else {
+			toolSets = append(toolSets, toolSet)
+		}
+		statuses = append(statuses, status)
+	}
+	return toolSets, statuses
+}
@@ -0,0 +1,204 @@
+package sdk
+
+import (
+	"log"
+	"strings"
+
+	"github.com/gonutz/wui/v2"
+
+	"github.com/package-register/gui/event"
+)
+
+// MenuSetupFunc 菜单栏注册回调
+type MenuSetupFunc func(m *MenuBar)
+
+// MenuBar 窗口菜单栏
+type MenuBar struct {
+	menus []*Menu
+}
+
+// AddMenu 添加顶层菜单，标题中 & 后的字母为 Alt 助记键，如 "文件(&F)"
+func (b *MenuBar) AddMenu(title string) *Menu {
+	m := &Menu{title: title}
+	b.menus = append(b.menus, m)
+	return m
+}
+
+// Menu 菜单，用作菜单栏中的下拉菜单、子菜单或右键菜单
+type Menu struct {
+	title string
+	items []*MenuItem
+}
+
+// NewMenu 创建右键菜单，通过 TabContext.SetContextMenu 关联到控件
+func NewMenu() *Menu {
+	return &Menu{}
+}
+
+// MenuItem 菜单项
+type MenuItem struct {
+	title       string
+	accelerator string
+	separator   bool
+	submenu     *Menu
+	checkable   bool
+	checked     bool
+	onClick     func()
+	onCheck     func(checked bool)
+
+	name   string          // 菜单路径，如 "文件/导出"，作为快捷键动作名称与事件数据
+	native *wui.MenuString // 菜单栏中的原生菜单项
+}
+
+// AddItem 添加菜单项；accelerator 如 "Ctrl+S"，为空表示无快捷键
+//
+// 菜单栏中的快捷键注册为应用级快捷键（动作名称为 "menu.菜单路径"，可被 WithKeymap 覆盖），
+// 右键菜单中的只作为提示显示。
+func (m *Menu) AddItem(title, accelerator string, handler func()) *MenuItem {
+	item := &MenuItem{title: title, accelerator: accelerator, onClick: handler}
+	m.items = append(m.items, item)
+	return item
+}
+
+// AddCheckItem 添加复选菜单项，点击时切换勾选状态后调用 handler
+func (m *Menu) AddCheckItem(title, accelerator string, checked bool, handler func(checked bool)) *MenuItem {
+	item := &MenuItem{title: title, accelerator: accelerator, checkable: true, checked: checked, onCheck: handler}
+	m.items = append(m.items, item)
+	return item
+}
+
+// AddSubmenu 添加子菜单
+func (m *Menu) AddSubmenu(title string) *Menu {
+	sub := &Menu{title: title}
+	m.items = append(m.items, &MenuItem{title: title, submenu: sub})
+	return sub
+}
+
+// AddSeparator 添加分隔线
+func (m *Menu) AddSeparator() {
+	m.items = append(m.items, &MenuItem{separator: true})
+}
+
+// Title 菜单项标题
+func (i *MenuItem) Title() string {
+	return i.title
+}
+
+// Checked 是否勾选
+func (i *MenuItem) Checked() bool {
+	return i.checked
+}
+
+// SetChecked 设置勾选状态，不调用 handler
+func (i *MenuItem) SetChecked(checked bool) {
+	i.checked = checked
+	if i.native != nil {
+		i.native.SetChecked(checked)
+	}
+}
+
+// label 菜单中显示的文字，快捷键右对齐显示
+func (i *MenuItem) label() string {
+	if i.accelerator == "" {
+		return i.title
+	}
+	return i.title + "\t" + i.accelerator
+}
+
+// activate 执行菜单项并发布 event.MenuClicked
+func (i *MenuItem) activate(app *App) {
+	if i.checkable {
+		i.SetChecked(!i.checked)
+		if i.onCheck != nil {
+			i.onCheck(i.checked)
+		}
+	} else if i.onClick != nil {
+		i.onClick()
+	}
+	app.events.Emit(event.MenuClicked, i.name)
+}
+
+// stripMnemonic 去掉标题中的助记键标记，"导出(&E)" → "导出"，"A&&B" → "A&B"
+func stripMnemonic(title string) string {
+	var sb strings.Builder
+	runes := []rune(title)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '(' && i+3 < len(runes) && runes[i+1] == '&' && runes[i+3] == ')' {
+			i += 3
+			continue
+		}
+		if r == '&' {
+			if i+1 < len(runes) && runes[i+1] == '&' {
+				sb.WriteRune('&')
+				i++
+			}
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// menuPath 子项的菜单路径
+func menuPath(parent, title string) string {
+	if parent == "" {
+		return stripMnemonic(title)
+	}
+	return parent + "/" + stripMnemonic(title)
+}
+
+// RegisterMenu 注册窗口菜单栏
+func (app *App) RegisterMenu(setup MenuSetupFunc) {
+	app.menuSetup = setup
+}
+
+// buildMenuBar 创建菜单栏并把菜单项的快捷键注册为应用级快捷键，须在设置窗口内部尺寸之前调用
+func (app *App) buildMenuBar() {
+	if app.menuSetup == nil {
+		return
+	}
+	bar := &MenuBar{}
+	app.menuSetup(bar)
+	mainMenu := wui.NewMainMenu()
+	for _, m := range bar.menus {
+		mainMenu.Add(app.nativeMenu(m, menuPath("", m.title)))
+	}
+	app.window.SetMenu(mainMenu)
+}
+
+// nativeMenu 转换为 wui 菜单
+func (app *App) nativeMenu(m *Menu, path string) *wui.Menu {
+	menu := wui.NewMenu(m.title)
+	for _, item := range m.items {
+		switch {
+		case item.separator:
+			menu.Add(wui.NewMenuSeparator())
+		case item.submenu != nil:
+			menu.Add(app.nativeMenu(item.submenu, menuPath(path, item.title)))
+		default:
+			item := item
+			item.name = menuPath(path, item.title)
+			app.bindMenuShortcut(item)
+			item.native = wui.NewMenuString(item.label())
+			item.native.SetChecked(item.checked)
+			item.native.SetOnClick(func() { item.activate(app) })
+			menu.Add(item.native)
+		}
+	}
+	return menu
+}
+
+// bindMenuShortcut 注册菜单项的快捷键，菜单中显示实际生效的键位；冲突时只显示菜单项
+func (app *App) bindMenuShortcut(item *MenuItem) {
+	s, err := app.addShortcut("", "menu."+item.name, item.name, item.accelerator, func() { item.activate(app) })
+	if err != nil {
+		log.Printf("Menu shortcut for %s disabled: %v", item.name, err)
+		item.accelerator = ""
+		return
+	}
+	item.accelerator = ""
+	if s.Bound() {
+		item.accelerator = s.Accelerator.String()
+	}
+}
@@ -0,0 +1,130 @@
+package sdk
+
+import (
+	w32 "github.com/gonutz/w32/v2"
+	"github.com/gonutz/wui/v2"
+)
+
+// MouseHandler 控件鼠标事件处理，坐标相对控件左上角
+type MouseHandler struct {
+	OnDown  func(button wui.MouseButton, x, y int)
+	OnUp    func(button wui.MouseButton, x, y int)
+	OnMove  func(x, y int)
+	OnWheel func(x, y int, delta float64)
+	OnLeave func() // 光标移到窗口内其他位置
+}
+
+// mouseTarget 注册了鼠标处理的控件
+type mouseTarget struct {
+	tab     *TabContext
+	control wui.Control
+	handler MouseHandler
+}
+
+// mouseRouter 将主窗口收到的鼠标事件分发给Tab内的控件
+//
+// PaintBox、Panel 等静态控件不接收鼠标消息，消息会穿透到主窗口，
+// 因此由主窗口统一按控件的屏幕位置命中测试后再分发。
+type mouseRouter struct {
+	app     *App
+	targets []*mouseTarget
+	capture *mouseTarget // 按下后捕获鼠标的控件，松开前事件都发给它
+	hover   *mouseTarget // 光标所在的控件
+}
+
+func newMouseRouter(app *App) *mouseRouter {
+	return &mouseRouter{app: app}
+}
+
+// HandleMouse 为Tab内的控件注册鼠标事件处理
+func (t *TabContext) HandleMouse(control wui.Control, handler MouseHandler) {
+	t.app.mouse.targets = append(t.app.mouse.targets, &mouseTarget{
+		tab:     t,
+		control: control,
+		handler: handler,
+	})
+}
+
+// setupMouseHandler 设置主窗口的鼠标事件分发
+func (app *App) setupMouseHandler() {
+	r := app.mouse
+	app.window.SetOnMouseDown(func(button wui.MouseButton, x, y int) {
+		target, lx, ly := r.hit(x, y)
+		if target == nil {
+			return
+		}
+		r.capture = target
+		w32.SetCapture(w32.HWND(app.window.Handle()))
+		if target.handler.OnDown != nil {
+			target.handler.OnDown(button, lx, ly)
+		}
+	})
+	app.window.SetOnMouseUp(func(button wui.MouseButton, x, y int) {
+		target := r.capture
+		r.capture = nil
+		w32.ReleaseCapture()
+		if target == nil {
+			return
+		}
+		lx, ly := r.local(target, x, y)
+		if target.handler.OnUp != nil {
+			target.handler.OnUp(button, lx, ly)
+		}
+	})
+	app.window.SetOnMouseMove(func(x, y int) {
+		target, lx, ly := r.capture, 0, 0
+		if target != nil {
+			lx, ly = r.local(target, x, y)
+		} else {
+			target, lx, ly = r.hit(x, y)
+			r.setHover(target)
+		}
+		if target != nil && target.handler.OnMove != nil {
+			target.handler.OnMove(lx, ly)
+		}
+	})
+	app.window.SetOnMouseWheel(func(x, y int, delta float64) {
+		// 滚轮消息的坐标是屏幕坐标
+		cx, cy, _ := w32.ScreenToClient(w32.HWND(app.window.Handle()), int(int16(x)), int(int16(y)))
+		target, lx, ly := r.hit(cx, cy)
+		if target != nil && target.handler.OnWheel != nil {
+			target.handler.OnWheel(lx, ly, delta)
+		}
+	})
+}
+
+// setHover 光标离开控件时通知
+func (r *mouseRouter) setHover(target *mouseTarget) {
+	if target == r.hover {
+		return
+	}
+	if r.hover != nil && r.hover.handler.OnLeave != nil {
+		r.hover.handler.OnLeave()
+	}
+	r.hover = target
+}
+
+// hit 找到主窗口坐标 (x, y) 下的控件，返回控件内坐标
+func (r *mouseRouter) hit(x, y int) (*mouseTarget, int, int) {
+	for i := len(r.targets) - 1; i >= 0; i-- {
+		target := r.targets[i]
+		if target.tab.name != r.app.activeTab || !wui.Visible(target.control) {
+			continue
+		}
+		lx, ly := r.local(target, x, y)
+		_, _, w, h := target.control.Bounds()
+		if lx >= 0 && ly >= 0 && lx < w && ly < h {
+			return target, lx, ly
+		}
+	}
+	return nil, 0, 0
+}
+
+// local 主窗口坐标转换为控件内坐标
+func (r *mouseRouter) local(target *mouseTarget, x, y int) (int, int) {
+	// 捕获鼠标后坐标可能为负，wui 传入的是无符号的低 16 位
+	x, y = int(int16(x)), int(int16(y))
+	sx, sy := w32.ClientToScreen(w32.HWND(r.app.window.Handle()), x, y)
+	rect := w32.GetWindowRect(w32.HWND(target.control.Handle()))
+	return sx - int(rect.Left), sy - int(rect.Top)
+}
@@ -0,0 +1,236 @@
+package sdk
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/package-register/gui/event"
+)
+
+// TextBlock 识别出的一段文字及其位置（图片坐标）
+type TextBlock struct {
+	Text       string
+	Bounds     image.Rectangle
+	Confidence float64 // 0~1，引擎不提供时为 0
+}
+
+// TextRecognizer 文字识别（OCR）后端
+//
+// 实现需返回 img.Bounds() 坐标系中的文字块，以便对截图的子区域识别后仍能对应到原图。
+type TextRecognizer interface {
+	Recognize(img image.Image) ([]TextBlock, error)
+}
+
+// TextRecognizerFunc 函数形式的文字识别后端
+type TextRecognizerFunc func(img image.Image) ([]TextBlock, error)
+
+// Recognize 实现 TextRecognizer
+func (f TextRecognizerFunc) Recognize(img image.Image) ([]TextBlock, error) {
+	return f(img)
+}
+
+// OCRResult 一次文字识别的结果
+type OCRResult struct {
+	Region image.Rectangle // 识别的区域
+	Blocks []TextBlock
+	Text   string // 按阅读顺序拼接的文字
+}
+
+// WithOCR 设置应用默认的文字识别后端
+func WithOCR(recognizer TextRecognizer) Option {
+	return func(a *App) { a.ocr = recognizer }
+}
+
+// Recognizer 应用默认的文字识别后端，未设置时为 nil
+func (app *App) Recognizer() TextRecognizer {
+	return app.ocr
+}
+
+// subImager 支持共享像素取子图的图片
+type subImager interface {
+	SubImage(r image.Rectangle) image.Image
+}
+
+// RecognizeRegion 识别图片中 region 内的文字，region 为空时识别整张图片
+func RecognizeRegion(recognizer TextRecognizer, img image.Image, region image.Rectangle) (*OCRResult, error) {
+	if recognizer == nil {
+		return nil, fmt.Errorf("未配置文字识别")
+	}
+	if img == nil {
+		return nil, ErrNoImage
+	}
+	if region.Empty() {
+		region = img.Bounds()
+	}
+	region = region.Intersect(img.Bounds())
+	if region.Empty() {
+		return nil, fmt.Errorf("识别区域不在图片内")
+	}
+
+	// 保持原图坐标，识别结果可直接对应到原图
+	sub := img
+	if region != img.Bounds() {
+		if s, ok := img.(subImager); ok {
+			sub = s.SubImage(region)
+		} else {
+			sub = rgbaOf(img).SubImage(region)
+		}
+	}
+
+	blocks, err := recognizer.Recognize(sub)
+	if err != nil {
+		return nil, fmt.Errorf("文字识别失败: %w", err)
+	}
+	SortTextBlocks(blocks)
+	return &OCRResult{Region: region, Blocks: blocks, Text: JoinTextBlocks(blocks)}, nil
+}
+
+// SortTextBlocks 按阅读顺序排序：先按行从上到下，同一行内从左到右
+func SortTextBlocks(blocks []TextBlock) {
+	i := 0
+	for _, line := range textLines(blocks) {
+		i += copy(blocks[i:], line)
+	}
+}
+
+// textLines 将文字块分行，行内按从左到右排序
+//
+// 文字块按顶部从上到下依次放入：与当前行的垂直范围重叠超过两者中较矮者一半时归入该行，否则另起一行。
+// 先分行再排序，避免把不可传递的“同一行”关系直接用作排序比较。
+func textLines(blocks []TextBlock) [][]TextBlock {
+	sorted := append([]TextBlock(nil), blocks...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Bounds.Min.Y < sorted[j].Bounds.Min.Y })
+
+	var lines [][]TextBlock
+	var band image.Rectangle // 当前行的范围
+	for _, block := range sorted {
+		if n := len(lines); n > 0 && sameLine(band, block.Bounds) {
+			lines[n-1] = append(lines[n-1], block)
+			band = band.Union(block.Bounds)
+			continue
+		}
+		lines = append(lines, []TextBlock{block})
+		band = block.Bounds
+	}
+	for _, line := range lines {
+		sort.SliceStable(line, func(i, j int) bool { return line[i].Bounds.Min.X < line[j].Bounds.Min.X })
+	}
+	return lines
+}
+
+func sameLine(a, b image.Rectangle) bool {
+	overlap := min(a.Max.Y, b.Max.Y) - max(a.Min.Y, b.Min.Y)
+	return overlap*2 > min(a.Dy(), b.Dy())
+}
+
+// JoinTextBlocks 按阅读顺序拼接文字块：同一行以空格分隔，不同行换行
+func JoinTextBlocks(blocks []TextBlock) string {
+	var sb strings.Builder
+	for i, line := range textLines(blocks) {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		for j, block := range line {
+			if j > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(block.Text)
+		}
+	}
+	return sb.String()
+}
+
+// CopyTextFromRegion 识别 region 内的文字并复制到剪贴板
+func CopyTextFromRegion(clipboard *Clipboard, recognizer TextRecognizer, img image.Image, region image.Rectangle) (*OCRResult, error) {
+	result, err := RecognizeRegion(recognizer, img, region)
+	if err != nil {
+		return nil, err
+	}
+	if result.Text == "" {
+		return result, fmt.Errorf("未识别到文字")
+	}
+	if err := clipboard.SetText(result.Text); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// WithCopyText 截图（或框选区域）后识别文字并复制到剪贴板；recognizer 为 nil 时使用应用默认后端
+//
+// 识别在打码之后进行，打码区域中的文字不会被复制。
+func WithCopyText(recognizer TextRecognizer) ScreenshotOption {
+	return func(c *screenshotConfig) {
+		c.copyText = true
+		c.recognizer = recognizer
+	}
+}
+
+// copyScreenshotText 截图流程中的复制文字动作，结果通过事件总线发布
+func (t *TabContext) copyScreenshotText(cfg *screenshotConfig, img image.Image) {
+	recognizer := cfg.recognizer
+	if recognizer == nil {
+		recognizer = t.app.ocr
+	}
+	result, err := CopyTextFromRegion(t.app.clipboard, recognizer, img, image.Rectangle{})
+	if err != nil {
+		log.Printf("Copy text from screenshot failed: %v", err)
+		return
+	}
+	if t.events != nil {
+		t.events.Emit(event.TextRecognized, result)
+	}
+}
+
+// FakeRecognizer 确定性的文字识别后端，用于测试
+//
+// 返回预设文字块中完全落在输入图片范围内的部分，按阅读顺序排列。
+type FakeRecognizer struct {
+	mu     sync.Mutex
+	blocks []TextBlock
+	err    error
+	calls  int
+}
+
+// NewFakeRecognizer 创建返回预设文字块的识别后端
+func NewFakeRecognizer(blocks ...TextBlock) *FakeRecognizer {
+	return &FakeRecognizer{blocks: blocks}
+}
+
+// SetError 之后的识别都返回 err，nil 表示恢复正常
+func (f *FakeRecognizer) SetError(err error) {
+	f.mu.Lock()
+	f.err = err
+	f.mu.Unlock()
+}
+
+// Calls 已调用识别的次数
+func (f *FakeRecognizer) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// Recognize 实现 TextRecognizer
+func (f *FakeRecognizer) Recognize(img image.Image) ([]TextBlock, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	var out []TextBlock
+	for _, block := range f.blocks {
+		if block.Bounds.In(img.Bounds()) {
+			if block.Confidence == 0 {
+				block.Confidence = 1
+			}
+			out = append(out, block)
+		}
+	}
+	SortTextBlocks(out)
+	return out, nil
+}
@@ -0,0 +1,84 @@
+package sdk
+
+import (
+	"errors"
+	"image"
+	"testing"
+)
+
+// jitteredBlocks 两行文字，同一行的文字块顶部有几像素的偏差，顺序打乱
+func jitteredBlocks() []TextBlock {
+	return []TextBlock{
+		{Text: "第二行", Bounds: image.Rect(10, 40, 80, 58)},
+		{Text: "世界", Bounds: image.Rect(60, 12, 100, 30)},
+		{Text: "！", Bounds: image.Rect(110, 8, 120, 26)}, // 行内最靠上，但在最右侧
+		{Text: "你好", Bounds: image.Rect(10, 10, 50, 28)},
+	}
+}
+
+func TestSortTextBlocksSameLine(t *testing.T) {
+	blocks := jitteredBlocks()
+	SortTextBlocks(blocks)
+	want := []string{"你好", "世界", "！", "第二行"}
+	for i, block := range blocks {
+		if block.Text != want[i] {
+			t.Fatalf("第 %d 个文字块 = %q，期望阅读顺序 %v", i, block.Text, want)
+		}
+	}
+	if got, want := JoinTextBlocks(blocks), "你好 世界 ！\n第二行"; got != want {
+		t.Errorf("JoinTextBlocks = %q，期望 %q", got, want)
+	}
+}
+
+func TestRecognizeRegionWithFakeRecognizer(t *testing.T) {
+	fake := NewFakeRecognizer(jitteredBlocks()...)
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+
+	result, err := RecognizeRegion(fake, img, image.Rectangle{})
+	if err != nil {
+		t.Fatalf("RecognizeRegion 出错: %v", err)
+	}
+	if want := "你好 世界 ！\n第二行"; result.Text != want {
+		t.Errorf("Text = %q，期望 %q", result.Text, want)
+	}
+	if result.Region != img.Rect {
+		t.Errorf("Region = %v，区域为空时应识别整张图片", result.Region)
+	}
+
+	// 子区域识别只返回区域内的文字，坐标仍是原图坐标
+	result, err = RecognizeRegion(fake, img, image.Rect(0, 0, 105, 35))
+	if err != nil {
+		t.Fatalf("RecognizeRegion 出错: %v", err)
+	}
+	if want := "你好 世界"; result.Text != want {
+		t.Errorf("子区域 Text = %q，期望 %q", result.Text, want)
+	}
+	if got := result.Blocks[1].Bounds; got != image.Rect(60, 12, 100, 30) {
+		t.Errorf("子区域文字块坐标 = %v，期望原图坐标", got)
+	}
+	if fake.Calls() != 2 {
+		t.Errorf("Calls() = %d，期望 2", fake.Calls())
+	}
+
+	errOCR := errors.New("引擎不可用")
+	fake.SetError(errOCR)
+	if _, err := RecognizeRegion(fake, img, image.Rectangle{}); !errors.Is(err, errOCR) {
+		t.Errorf("识别失败时的错误 = %v，期望包装 %v", err, errOCR)
+	}
+}
+
+func TestCopyTextFromRegion(t *testing.T) {
+	clipboard := NewClipboard(NewMemoryClipboard(), nil)
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+
+	if _, err := CopyTextFromRegion(clipboard, NewFakeRecognizer(jitteredBlocks()...), img, image.Rectangle{}); err != nil {
+		t.Fatalf("CopyTextFromRegion 出错: %v", err)
+	}
+	if got, _ := clipboard.Text(); got != "你好 世界 ！\n第二行" {
+		t.Errorf("剪贴板文字 = %q", got)
+	}
+
+	if _, err := CopyTextFromRegion(clipboard, NewFakeRecognizer(), img, image.Rectangle{}); err == nil {
+		t.Error("没有识别到文字时应返回错误")
+	}
+}
@@ -0,0 +1,330 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gonutz/wui/v2"
+	"github.com/package-register/gui/imaging"
+)
+
+// RecordFormat 录屏输出格式
+type RecordFormat string
+
+const (
+	RecordGIF  RecordFormat = "gif"
+	RecordAPNG RecordFormat = "apng"
+)
+
+// 录屏默认参数
+const (
+	defaultRecordFPS       = 10
+	defaultRecordDuration  = 10 * time.Second
+	defaultRecordMaxFrames = 600 // 防止文件无限增长
+	recordFrameTolerance   = 8   // 像素通道差值不超过此值视为重复帧
+	recordEncodeQueue      = 4   // 等待编码的帧数上限
+	recordTimeLayout       = "20060102_150405"
+)
+
+// ErrRecordingCancelled 录屏被取消
+var ErrRecordingCancelled = errors.New("录屏已取消")
+
+// RecordConfig 录屏配置
+type RecordConfig struct {
+	Target    CaptureTarget // 录制目标，默认主显示器
+	Duration  time.Duration // 录制时长，默认 10 秒
+	FPS       int           // 帧率，默认 10
+	Format    RecordFormat  // 输出格式，默认 GIF
+	Path      string        // 输出文件，为空时按时间生成
+	MaxColors int           // GIF 调色板颜色数，0 表示 256
+	MaxFrames int           // 最多帧数，0 表示默认
+	Redactor  *Redactor     // 每帧打码
+}
+
+// RecordProgress 录屏进度
+type RecordProgress struct {
+	Phase string // "capture" 录制中（帧同时编码），"encode" 录制结束，等待剩余的帧编码并写出文件尾
+	Done  int
+	Total int
+}
+
+// withDefaults 补全默认参数
+func (c RecordConfig) withDefaults() RecordConfig {
+	if c.Target == (CaptureTarget{}) {
+		c.Target = CaptureDisplay(0)
+	}
+	if c.Duration <= 0 {
+		c.Duration = defaultRecordDuration
+	}
+	if c.FPS <= 0 {
+		c.FPS = defaultRecordFPS
+	}
+	if c.Format == "" {
+		c.Format = RecordGIF
+	}
+	if c.MaxFrames <= 0 {
+		c.MaxFrames = defaultRecordMaxFrames
+	}
+	if c.Path == "" {
+		ext := ".gif"
+		if c.Format == RecordAPNG {
+			ext = ".png"
+		}
+		c.Path = "recording_" + time.Now().Format(recordTimeLayout) + ext
+	}
+	return c
+}
+
+// Record 按配置录屏并编码到文件，返回文件路径；ctx 取消时返回 ErrRecordingCancelled
+func Record(ctx context.Context, cfg RecordConfig, progress func(RecordProgress)) (string, error) {
+	cfg = cfg.withDefaults()
+	if cfg.Format != RecordGIF && cfg.Format != RecordAPNG {
+		return "", fmt.Errorf("不支持的录屏格式: %s", cfg.Format)
+	}
+	report := func(phase string, done, total int) {
+		if progress != nil {
+			progress(RecordProgress{Phase: phase, Done: done, Total: total})
+		}
+	}
+
+	f, err := os.Create(cfg.Path)
+	if err != nil {
+		return "", fmt.Errorf("创建录屏文件失败: %w", err)
+	}
+	var enc frameEncoder
+	if cfg.Format == RecordAPNG {
+		enc = imaging.NewAPNGEncoder(f)
+	} else {
+		enc = imaging.NewGIFEncoder(f, cfg.MaxColors)
+	}
+
+	err = recordFrames(ctx, cfg, enc, report)
+	if err == nil {
+		if err = enc.Close(); err != nil {
+			err = fmt.Errorf("编码录屏失败: %w", err)
+		}
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(cfg.Path)
+		return "", err
+	}
+	report("encode", 1, 1)
+	return cfg.Path, nil
+}
+
+// frameEncoder 逐帧编码动画，imaging.GIFEncoder 与 imaging.APNGEncoder 实现
+type frameEncoder interface {
+	Add(f imaging.Frame) error
+	Close() error
+}
+
+// recordFrames 在录制时长内按帧率截图，由单独的 goroutine 逐帧交给 enc 编码
+//
+// 编码（尤其是 GIF 的量化与抖动）远慢于截图，放在截图循环里会拖慢帧率并使录制远超设定时长。
+// 截图与编码之间的队列是有界的，编码跟不上时截图等待，内存占用不随录制时长增长。
+func recordFrames(ctx context.Context, cfg RecordConfig, enc frameEncoder, report func(string, int, int)) error {
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	queue := make(chan imaging.Frame, recordEncodeQueue)
+	encoded := make(chan error, 1)
+	go func() {
+		var err error
+		for f := range queue {
+			if err != nil {
+				continue
+			}
+			if err = enc.Add(f); err != nil {
+				stop() // 编码失败后停止截图
+			}
+		}
+		encoded <- err
+	}()
+
+	err := captureFrames(ctx, cfg, queue, report)
+	close(queue)
+	if encErr := <-encoded; encErr != nil {
+		return fmt.Errorf("编码录屏失败: %w", encErr)
+	}
+	return err
+}
+
+// captureFrames 截图直到录制时长结束或达到最多帧数，重复帧合并为上一帧的显示时长
+//
+// 只保留最近一张不同的画面：它在下一张不同的画面到来（或录制结束）时才送入队列，
+// 显示时长取实际的截图时间差，截图跟不上帧率时回放速度仍与录制时一致。
+func captureFrames(ctx context.Context, cfg RecordConfig, queue chan<- imaging.Frame, report func(string, int, int)) error {
+	interval := time.Second / time.Duration(cfg.FPS)
+	total := int(cfg.Duration / interval)
+	if total > cfg.MaxFrames {
+		total = cfg.MaxFrames
+	}
+	if total < 1 {
+		total = 1
+	}
+	start := time.Now()
+	deadline := start.Add(cfg.Duration)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var pending image.Image
+	var pendingAt time.Time
+	send := func(until time.Time) error {
+		select {
+		case queue <- imaging.Frame{Image: pending, Delay: until.Sub(pendingAt)}:
+			return nil
+		case <-ctx.Done():
+			return ErrRecordingCancelled
+		}
+	}
+	for i := 0; i < cfg.MaxFrames && time.Now().Before(deadline); i++ {
+		shot, err := Capture(cfg.Target)
+		if err != nil {
+			return err
+		}
+		var img image.Image = shot
+		if cfg.Redactor != nil {
+			if img, err = cfg.Redactor.Redact(img); err != nil {
+				return err
+			}
+		}
+		if pending == nil || imaging.ChangeRatio(pending, img, recordFrameTolerance) > 0 {
+			if pending != nil {
+				if err := send(shot.Time); err != nil {
+					return err
+				}
+			}
+			pending, pendingAt = img, shot.Time
+		}
+		// 进度按已录制的时间推进，截图变慢时也不会停在中途
+		done := max(i+1, int(time.Since(start)/interval))
+		report("capture", min(done, total), total)
+
+		select {
+		case <-ctx.Done():
+			return ErrRecordingCancelled
+		case <-ticker.C:
+		}
+	}
+	report("encode", 0, 1)
+	// 最后一帧显示到录制结束
+	return send(time.Now())
+}
+
+// Recorder 录屏控件：录制/取消按钮、进度条与状态
+//
+// 录制在后台 goroutine 中进行，控件的更新都通过 app.invoke 转交界面线程。
+type Recorder struct {
+	app      *App
+	config   RecordConfig
+	button   *wui.Button
+	progress *wui.ProgressBar
+	status   *wui.Label
+	callback func(path string, err error)
+
+	mu     sync.Mutex
+	cancel context.CancelFunc // 录制期间非空
+}
+
+// AddRecorder 添加录屏控件，录制结束后回调输出文件路径
+func (t *TabContext) AddRecorder(x, y, w, h int, cfg RecordConfig, callback func(path string, err error)) *Recorder {
+	const padding = 8
+	const buttonWidth = 100
+
+	r := &Recorder{app: t.app, config: cfg, callback: callback}
+	r.button = t.AddButton("录制", x, y, buttonWidth, h, r.toggle)
+	r.progress = t.AddProgressBar(x+buttonWidth+padding, y, w-buttonWidth-padding, h/2)
+	r.status = t.AddLabel("就绪", x+buttonWidth+padding, y+h/2+2, w-buttonWidth-padding, h-h/2-2)
+	return r
+}
+
+// Recording 是否正在录制或编码
+func (r *Recorder) Recording() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cancel != nil
+}
+
+// Start 开始录制，正在录制时无效
+func (r *Recorder) Start() {
+	r.mu.Lock()
+	if r.cancel != nil {
+		r.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.mu.Unlock()
+
+	r.app.invoke(func() {
+		r.button.SetText("取消")
+		r.progress.SetValue(0)
+		r.status.SetText("录制中…")
+	})
+
+	go func() {
+		path, err := Record(ctx, r.config, r.onProgress)
+		cancel()
+		r.mu.Lock()
+		r.cancel = nil
+		r.mu.Unlock()
+
+		r.app.invoke(func() {
+			r.button.SetText("录制")
+			switch {
+			case errors.Is(err, ErrRecordingCancelled):
+				r.status.SetText("已取消")
+			case err != nil:
+				r.status.SetText(fmt.Sprintf("录制失败: %v", err))
+			default:
+				r.progress.SetValue(1)
+				r.status.SetText("已保存: " + path)
+			}
+		})
+		if r.callback != nil {
+			r.callback(path, err)
+		}
+	}()
+}
+
+// Cancel 取消录制或编码
+func (r *Recorder) Cancel() {
+	r.mu.Lock()
+	cancel := r.cancel
+	r.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+func (r *Recorder) toggle() {
+	if r.Recording() {
+		r.Cancel()
+	} else {
+		r.Start()
+	}
+}
+
+// onProgress 帧在录制时即被编码，进度条按录制时间推进；在录制 goroutine 中调用
+func (r *Recorder) onProgress(p RecordProgress) {
+	if p.Total <= 0 {
+		return
+	}
+	r.app.invoke(func() {
+		if p.Phase == "encode" {
+			r.status.SetText("正在写入文件…")
+			return
+		}
+		r.status.SetText(fmt.Sprintf("录制中 %d/%d", p.Done, p.Total))
+		r.progress.SetValue(float64(p.Done) / float64(p.Total))
+	})
+}
@@ -0,0 +1,244 @@
+package sdk
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"regexp"
+	"strings"
+
+	w32 "github.com/gonutz/w32/v2"
+
+	"github.com/package-register/gui/imaging"
+)
+
+// RedactMode 打码方式
+type RedactMode int
+
+const (
+	RedactBlur     RedactMode = iota // 模糊
+	RedactPixelate                   // 马赛克
+	RedactFill                       // 纯色覆盖，不保留任何原始像素
+)
+
+// 打码默认参数
+const (
+	defaultRedactStrength = 12 // 模糊半径或马赛克色块边长
+	defaultRedactPadding  = 4  // 检测区域向外扩展的像素
+)
+
+// RedactionDetector 敏感区域检测器
+type RedactionDetector interface {
+	// Name 检测器名称，用于错误信息
+	Name() string
+	// Detect 返回需要打码的区域（图片坐标）
+	Detect(img image.Image) ([]image.Rectangle, error)
+}
+
+// Redactor 截图打码管道：依次运行检测器，再对所有命中区域打码
+//
+// 任一检测器失败时整张截图都不会交出，避免未打码的内容被保存或发送。
+type Redactor struct {
+	Mode      RedactMode
+	Strength  int // 模糊半径或马赛克色块边长，0 表示默认
+	Padding   int // 区域向外扩展的像素，0 表示默认，负数表示不扩展
+	Detectors []RedactionDetector
+}
+
+// NewRedactor 创建使用模糊打码的管道
+func NewRedactor(detectors ...RedactionDetector) *Redactor {
+	return &Redactor{Mode: RedactBlur, Detectors: detectors}
+}
+
+// WithRedaction 截图交给回调前先经过打码管道
+func WithRedaction(r *Redactor) ScreenshotOption {
+	return func(c *screenshotConfig) { c.redactor = r }
+}
+
+// Redact 对图片打码，返回新的截图，原图不会被修改
+func (r *Redactor) Redact(img image.Image) (*Screenshot, error) {
+	var regions []image.Rectangle
+	for _, d := range r.Detectors {
+		found, err := d.Detect(img)
+		if err != nil {
+			return nil, fmt.Errorf("敏感区域检测失败（%s）: %w", d.Name(), err)
+		}
+		regions = append(regions, found...)
+	}
+
+	padding := r.Padding
+	if padding == 0 {
+		padding = defaultRedactPadding
+	}
+	strength := r.Strength
+	if strength <= 0 {
+		strength = defaultRedactStrength
+	}
+
+	b := img.Bounds()
+	dst := image.NewRGBA(b)
+	draw.Draw(dst, b, img, b.Min, draw.Src)
+
+	var applied []image.Rectangle
+	for _, region := range regions {
+		if padding > 0 {
+			region = region.Inset(-padding)
+		}
+		region = region.Intersect(b)
+		if region.Empty() {
+			continue
+		}
+		switch r.Mode {
+		case RedactPixelate:
+			imaging.Pixelate(dst, region, strength)
+		case RedactFill:
+			draw.Draw(dst, region, image.Black, image.Point{}, draw.Src)
+		default:
+			imaging.Blur(dst, region, strength)
+		}
+		applied = append(applied, region)
+	}
+
+	shot := &Screenshot{RGBA: dst, Display: -1, ScreenBounds: b}
+	if info, ok := ScreenshotInfo(img); ok {
+		shot.Target = info.Target
+		shot.Display = info.Display
+		shot.ScreenBounds = info.ScreenBounds
+		shot.Time = info.Time
+		shot.Redactions = append(shot.Redactions, info.Redactions...)
+	}
+	shot.Redactions = append(shot.Redactions, applied...)
+	shot.redacted = true
+	return shot, nil
+}
+
+// IsRedacted 图片是否已经过打码管道
+func IsRedacted(img image.Image) bool {
+	shot, ok := ScreenshotInfo(img)
+	return ok && shot.redacted
+}
+
+// toImageRect 将虚拟桌面坐标转换为图片坐标，非截图图片按图片坐标处理
+func toImageRect(img image.Image, screen image.Rectangle) image.Rectangle {
+	shot, ok := ScreenshotInfo(img)
+	if !ok {
+		return screen
+	}
+	return screen.Sub(shot.ScreenBounds.Min).Add(shot.Rect.Min)
+}
+
+// --- 内置检测器 ---
+
+// regionDetector 固定屏幕区域
+type regionDetector struct {
+	regions []image.Rectangle
+}
+
+// RegionDetector 固定屏幕区域检测器，区域为虚拟桌面坐标
+func RegionDetector(regions ...image.Rectangle) RedactionDetector {
+	return &regionDetector{regions: regions}
+}
+
+func (d *regionDetector) Name() string { return "region" }
+
+func (d *regionDetector) Detect(img image.Image) ([]image.Rectangle, error) {
+	rects := make([]image.Rectangle, 0, len(d.regions))
+	for _, r := range d.regions {
+		rects = append(rects, toImageRect(img, r))
+	}
+	return rects, nil
+}
+
+// windowDetector 按标题匹配的窗口区域
+type windowDetector struct {
+	titles []string
+}
+
+// WindowDetector 窗口区域检测器，打码标题包含任一关键字的可见窗口（如密码管理器、聊天软件）
+func WindowDetector(titles ...string) RedactionDetector {
+	return &windowDetector{titles: titles}
+}
+
+func (d *windowDetector) Name() string { return "window" }
+
+func (d *windowDetector) Detect(img image.Image) ([]image.Rectangle, error) {
+	var rects []image.Rectangle
+	w32.EnumWindows(func(window w32.HWND) bool {
+		if !w32.IsWindowVisible(window) {
+			return true
+		}
+		if ok, cloaked := w32.DwmGetWindowAttributeCLOAKED(window); ok && cloaked != 0 {
+			return true
+		}
+		title := w32.GetWindowText(window)
+		if title == "" || !d.matches(title) {
+			return true
+		}
+		r := *w32.GetWindowRect(window)
+		if ok, frame := w32.DwmGetWindowAttributeEXTENDED_FRAME_BOUNDS(window); ok {
+			r = frame
+		}
+		screen := image.Rect(int(r.Left), int(r.Top), int(r.Right), int(r.Bottom))
+		rects = append(rects, toImageRect(img, screen))
+		return true
+	})
+	return rects, nil
+}
+
+func (d *windowDetector) matches(title string) bool {
+	for _, t := range d.titles {
+		if t != "" && strings.Contains(title, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// --- 文字识别 ---
+
+// 常用的敏感文字模式
+var (
+	PatternEmail      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	PatternPhone      = regexp.MustCompile(`\b1[3-9]\d{9}\b`)
+	PatternIDCard     = regexp.MustCompile(`\b\d{17}[\dXx]\b`)
+	PatternBankCard   = regexp.MustCompile(`\b(?:\d[ -]?){15,18}\d\b`)
+	PatternAPIKey     = regexp.MustCompile(`\b(?:sk|pk|ak)-[A-Za-z0-9_-]{16,}\b`)
+	SensitivePatterns = []*regexp.Regexp{PatternEmail, PatternPhone, PatternIDCard, PatternBankCard, PatternAPIKey}
+)
+
+// textPatternDetector 识别文字后按正则匹配
+type textPatternDetector struct {
+	recognizer TextRecognizer
+	patterns   []*regexp.Regexp
+}
+
+// TextPatternDetector 文字模式检测器：用 recognizer 识别文字，打码匹配任一模式的文字块；
+// 未指定模式时使用 SensitivePatterns
+func TextPatternDetector(recognizer TextRecognizer, patterns ...*regexp.Regexp) RedactionDetector {
+	if len(patterns) == 0 {
+		patterns = SensitivePatterns
+	}
+	return &textPatternDetector{recognizer: recognizer, patterns: patterns}
+}
+
+func (d *textPatternDetector) Name() string { return "text-pattern" }
+
+func (d *textPatternDetector) Detect(img image.Image) ([]image.Rectangle, error) {
+	if d.recognizer == nil {
+		return nil, fmt.Errorf("未配置文字识别")
+	}
+	blocks, err := d.recognizer.Recognize(img)
+	if err != nil {
+		return nil, err
+	}
+	var rects []image.Rectangle
+	for _, block := range blocks {
+		for _, p := range d.patterns {
+			if p.MatchString(block.Text) {
+				rects = append(rects, block.Bounds)
+				break
+			}
+		}
+	}
+	return rects, nil
+}
@@ -0,0 +1,224 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	w32 "github.com/gonutz/w32/v2"
+	"github.com/gonutz/wui/v2"
+
+	"github.com/package-register/gui/imaging"
+)
+
+// ErrCaptureCancelled 用户取消了区域选择
+var ErrCaptureCancelled = errors.New("截图已取消")
+
+// 选区遮罩外观
+const (
+	overlayDimFactor   = 0.5 // 选区外的亮度
+	magnifierRadius    = 7   // 放大镜取样半径（像素）
+	magnifierCellSize  = 8   // 放大后每个像素的边长
+	magnifierOffset    = 20  // 放大镜相对鼠标的偏移
+	overlayLabelHeight = 20
+)
+
+var (
+	overlayAccent = wui.RGB(0, 120, 215)
+	overlayText   = wui.RGB(255, 255, 255)
+	overlayLabel  = wui.RGB(32, 32, 32)
+)
+
+// WithRegionSelect 截图后显示全屏遮罩，由用户拖拽选择区域，只返回选中的部分；
+// 按 Esc 或右键取消时回调收到 ErrCaptureCancelled
+func WithRegionSelect() ScreenshotOption {
+	return func(c *screenshotConfig) { c.selectRegion = true }
+}
+
+// regionOverlay 区域选择遮罩窗口
+type regionOverlay struct {
+	window   *wui.Window
+	paintBox *wui.PaintBox
+	shot     *Screenshot
+	frozen   *wui.Image
+	dimmed   *wui.Image
+	sel      *imaging.Selection
+	mouse    image.Point // 图片坐标
+	result   image.Rectangle
+}
+
+// SelectRegion 在冻结的截图上显示全屏遮罩，返回用户框选的部分
+//
+// 遮罩是模态窗口，必须在界面线程上调用（如按钮回调中）；在后台 goroutine 中请使用 App.SelectRegion。
+func SelectRegion(img image.Image) (*Screenshot, error) {
+	shot, ok := ScreenshotInfo(img)
+	if !ok {
+		return nil, fmt.Errorf("区域选择需要 Capture 返回的截图")
+	}
+
+	o := &regionOverlay{
+		window:   wui.NewWindow(),
+		paintBox: wui.NewPaintBox(),
+		shot:     shot,
+		frozen:   wui.NewImage(shot.RGBA),
+		dimmed:   wui.NewImage(imaging.Dim(shot.RGBA, overlayDimFactor)),
+		sel:      imaging.NewSelection(shot.Rect),
+		mouse:    image.Pt(-1, -1),
+	}
+
+	sb := shot.ScreenBounds
+	o.window.SetTitle("选择截图区域")
+	o.window.SetHasBorder(false)
+	o.window.SetResizable(false)
+	o.window.SetBounds(sb.Min.X, sb.Min.Y, sb.Dx(), sb.Dy())
+	o.window.SetCursor(wui.CursorCross)
+
+	o.paintBox.SetBounds(0, 0, sb.Dx(), sb.Dy())
+	o.paintBox.SetOnPaint(o.paint)
+	o.window.Add(o.paintBox)
+
+	o.window.SetOnShow(func() {
+		w32.SetForegroundWindow(w32.HWND(o.window.Handle()))
+	})
+	o.window.SetOnMouseDown(o.mouseDown)
+	o.window.SetOnMouseMove(o.mouseMove)
+	o.window.SetOnMouseUp(o.mouseUp)
+	o.window.SetOnKeyDown(func(key int) {
+		if key == wui.KeyEscape {
+			o.window.Close()
+		}
+	})
+
+	if err := o.window.ShowModal(); err != nil {
+		return nil, err
+	}
+	if o.result.Empty() {
+		return nil, ErrCaptureCancelled
+	}
+
+	cropped, err := imaging.Crop(shot.RGBA, o.result)
+	if err != nil {
+		return nil, err
+	}
+	screen := o.result.Sub(shot.Rect.Min).Add(sb.Min)
+	return &Screenshot{
+		RGBA:         cropped,
+		Target:       CaptureRect(screen),
+		Display:      displayContaining(screen),
+		ScreenBounds: screen,
+		Time:         shot.Time,
+	}, nil
+}
+
+// SelectRegion 同包级的 SelectRegion，可在任意 goroutine 中调用，遮罩转交界面线程显示
+func (app *App) SelectRegion(img image.Image) (shot *Screenshot, err error) {
+	app.invoke(func() { shot, err = SelectRegion(img) })
+	return shot, err
+}
+
+// toImage 窗口坐标转换为图片坐标
+func (o *regionOverlay) toImage(x, y int) image.Point {
+	return image.Pt(x, y).Add(o.shot.Rect.Min)
+}
+
+// toWindow 图片坐标转换为窗口坐标
+func (o *regionOverlay) toWindow(p image.Point) image.Point {
+	return p.Sub(o.shot.Rect.Min)
+}
+
+func (o *regionOverlay) mouseDown(button wui.MouseButton, x, y int) {
+	switch button {
+	case wui.MouseButtonLeft:
+		o.sel.Begin(o.toImage(x, y))
+		o.paintBox.Paint()
+	case wui.MouseButtonRight:
+		// 拖拽中右键放弃当前选区，否则取消截图
+		if o.sel.Active() {
+			o.sel.Reset()
+			o.paintBox.Paint()
+		} else {
+			o.window.Close()
+		}
+	}
+}
+
+func (o *regionOverlay) mouseMove(x, y int) {
+	o.mouse = o.toImage(x, y)
+	o.sel.Update(o.mouse)
+	o.paintBox.Paint()
+}
+
+func (o *regionOverlay) mouseUp(button wui.MouseButton, x, y int) {
+	if button != wui.MouseButtonLeft || !o.sel.Active() {
+		return
+	}
+	r := o.sel.End(o.toImage(x, y))
+	if !o.sel.Done() {
+		// 单击或零面积选区，重新开始
+		o.sel.Reset()
+		o.paintBox.Paint()
+		return
+	}
+	o.result = r
+	o.window.Close()
+}
+
+// paint 绘制变暗的截图、高亮选区、尺寸读数与放大镜
+func (o *regionOverlay) paint(canvas *wui.Canvas) {
+	canvas.DrawImage(o.dimmed, o.dimmed.Bounds(), 0, 0)
+
+	r := o.sel.Rect()
+	if r.Empty() {
+		o.drawLabel(canvas, canvas.Width()/2-100, 16, "拖拽选择区域，Esc 或右键取消")
+	} else {
+		topLeft := o.toWindow(r.Min)
+		canvas.DrawImage(o.frozen, wui.Rect(topLeft.X, topLeft.Y, r.Dx(), r.Dy()), topLeft.X, topLeft.Y)
+		canvas.DrawRect(topLeft.X-1, topLeft.Y-1, r.Dx()+2, r.Dy()+2, overlayAccent)
+
+		labelY := topLeft.Y - overlayLabelHeight - 4
+		if labelY < 0 {
+			labelY = topLeft.Y + 4
+		}
+		o.drawLabel(canvas, topLeft.X, labelY, o.sel.SizeText())
+	}
+
+	if o.mouse.In(o.shot.Rect) {
+		o.drawMagnifier(canvas)
+	}
+}
+
+// drawLabel 绘制带底色的文字
+func (o *regionOverlay) drawLabel(canvas *wui.Canvas, x, y int, text string) {
+	w, _ := canvas.TextExtent(text)
+	canvas.FillRect(x, y, w+12, overlayLabelHeight, overlayLabel)
+	canvas.TextRectFormat(x, y, w+12, overlayLabelHeight, text, wui.FormatCenter, overlayText)
+}
+
+// drawMagnifier 在鼠标旁绘制像素放大镜和坐标/颜色读数
+func (o *regionOverlay) drawMagnifier(canvas *wui.Canvas) {
+	grid := imaging.Magnify(o.shot.RGBA, o.mouse, magnifierRadius)
+	size := len(grid) * magnifierCellSize
+
+	// 靠近屏幕边缘时翻转到鼠标另一侧
+	pos := o.toWindow(o.mouse).Add(image.Pt(magnifierOffset, magnifierOffset))
+	if pos.X+size > canvas.Width() {
+		pos.X -= size + magnifierOffset*2
+	}
+	if pos.Y+size+overlayLabelHeight > canvas.Height() {
+		pos.Y -= size + overlayLabelHeight + magnifierOffset*2
+	}
+
+	for gy, row := range grid {
+		for gx, c := range row {
+			canvas.FillRect(pos.X+gx*magnifierCellSize, pos.Y+gy*magnifierCellSize,
+				magnifierCellSize, magnifierCellSize, wui.RGB(c.R, c.G, c.B))
+		}
+	}
+	center := magnifierRadius * magnifierCellSize
+	canvas.DrawRect(pos.X+center, pos.Y+center, magnifierCellSize, magnifierCellSize, overlayAccent)
+	canvas.DrawRect(pos.X-1, pos.Y-1, size+2, size+2, overlayAccent)
+
+	c := grid[magnifierRadius][magnifierRadius]
+	screen := o.mouse.Sub(o.shot.Rect.Min).Add(o.shot.ScreenBounds.Min)
+	o.drawLabel(canvas, pos.X, pos.Y+size+2, fmt.Sprintf("%d,%d  #%02X%02X%02X", screen.X, screen.Y, c.R, c.G, c.B))
+}
@@ -0,0 +1,233 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gonutz/wui/v2"
+	"github.com/package-register/gui/imaging"
+)
+
+// 保存图片相关错误
+var (
+	ErrNoImage       = errors.New("没有可保存的图片")
+	ErrSaveCancelled = errors.New("已取消保存")
+)
+
+// maxUniqueAttempts 生成不重名文件名时的最大尝试次数
+const maxUniqueAttempts = 10000
+
+// SaveOption 保存选项
+type SaveOption func(*saveConfig)
+
+// saveConfig 保存配置
+type saveConfig struct {
+	format    imaging.Format
+	quality   int
+	metadata  imaging.Metadata
+	overwrite bool
+}
+
+// WithFormat 指定保存格式，默认按扩展名推断，无扩展名时为 PNG
+func WithFormat(format imaging.Format) SaveOption {
+	return func(c *saveConfig) { c.format = format }
+}
+
+// WithJPEGQuality 指定 JPEG 质量（1-100）
+func WithJPEGQuality(quality int) SaveOption {
+	return func(c *saveConfig) { c.quality = quality }
+}
+
+// WithMetadata 追加一条写入文件的元数据
+func WithMetadata(key, value string) SaveOption {
+	return func(c *saveConfig) {
+		if c.metadata == nil {
+			c.metadata = imaging.Metadata{}
+		}
+		c.metadata[key] = value
+	}
+}
+
+// WithOverwrite 目标文件已存在时覆盖，默认自动改名
+func WithOverwrite() SaveOption {
+	return func(c *saveConfig) { c.overwrite = true }
+}
+
+// ExpandFilename 展开文件名模板
+//
+// 支持的占位符：{date} 日期，{time} 日期与时间，{display} 显示器序号（全部显示器为 all，
+// 区域为 region），{width} 与 {height} 图片尺寸。截图使用截图时间，其他图片使用 now。
+func ExpandFilename(template string, img image.Image, now time.Time) string {
+	display := "0"
+	width, height := 0, 0
+	if img != nil {
+		width, height = img.Bounds().Dx(), img.Bounds().Dy()
+	}
+	if shot, ok := ScreenshotInfo(img); ok {
+		now = shot.Time
+		switch {
+		case shot.Target.Kind == CaptureKindAll:
+			display = "all"
+		case shot.Display >= 0:
+			display = strconv.Itoa(shot.Display)
+		default:
+			display = "region"
+		}
+	}
+	return strings.NewReplacer(
+		"{date}", now.Format("20060102"),
+		"{time}", now.Format("20060102_150405"),
+		"{display}", display,
+		"{width}", strconv.Itoa(width),
+		"{height}", strconv.Itoa(height),
+	).Replace(template)
+}
+
+// CreateUnique 新建文件，文件已存在时在扩展名前追加 _1、_2… 直到不重名
+//
+// 使用 O_EXCL 创建以占用文件名，并发保存同名文件时不会互相覆盖；实际路径为返回文件的 Name()。
+func CreateUnique(path string) (*os.File, error) {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	candidate := path
+	for i := 1; i < maxUniqueAttempts; i++ {
+		f, err := os.OpenFile(candidate, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if !errors.Is(err, os.ErrExist) {
+			return f, err
+		}
+		candidate = fmt.Sprintf("%s_%d%s", base, i, ext)
+	}
+	return nil, fmt.Errorf("无法为 %s 生成不重名的文件名", path)
+}
+
+// screenshotMetadata 截图的时间与屏幕位置
+func screenshotMetadata(img image.Image) imaging.Metadata {
+	meta := imaging.Metadata{"Software": "oAo Agent"}
+	shot, ok := ScreenshotInfo(img)
+	if !ok {
+		return meta
+	}
+	r := shot.ScreenBounds
+	meta["Creation Time"] = shot.Time.Format(time.RFC3339)
+	meta["Capture Target"] = shot.Target.String()
+	meta["Screen Bounds"] = fmt.Sprintf("%d,%d,%d,%d", r.Min.X, r.Min.Y, r.Dx(), r.Dy())
+	if shot.Display >= 0 {
+		meta["Display"] = strconv.Itoa(shot.Display)
+	}
+	if IsRedacted(shot) {
+		meta["Redacted"] = strconv.Itoa(len(shot.Redactions))
+	}
+	return meta
+}
+
+// SaveImage 保存图片，path 可包含 ExpandFilename 占位符，返回实际写入的路径
+//
+// 截图会自动写入截图时间、显示器与屏幕位置等元数据（PNG 与 JPEG）。
+func SaveImage(img image.Image, path string, opts ...SaveOption) (string, error) {
+	if img == nil {
+		return "", ErrNoImage
+	}
+	cfg := &saveConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	path = ExpandFilename(path, img, time.Now())
+	if cfg.format == "" {
+		cfg.format, _ = imaging.FormatFromPath(path)
+	}
+	if filepath.Ext(path) == "" {
+		path += cfg.format.Ext()
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return "", fmt.Errorf("创建目录失败: %w", err)
+		}
+	}
+
+	meta := screenshotMetadata(img)
+	for k, v := range cfg.metadata {
+		meta[k] = v
+	}
+
+	var f *os.File
+	var err error
+	if cfg.overwrite {
+		f, err = os.Create(path)
+	} else {
+		f, err = CreateUnique(path)
+	}
+	if err != nil {
+		return "", err
+	}
+	path = f.Name()
+	err = imaging.Encode(f, img, imaging.EncodeOptions{Format: cfg.format, Quality: cfg.quality, Metadata: meta})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("保存图片失败: %w", err)
+	}
+	return path, nil
+}
+
+// SaveDialogFunc 另存为对话框，返回用户选择的路径；取消时 ok 为 false
+type SaveDialogFunc func(suggested string) (path string, ok bool)
+
+// WithSaveDialog 替换默认的另存为对话框
+func WithSaveDialog(dialog SaveDialogFunc) Option {
+	return func(a *App) { a.saveDialog = dialog }
+}
+
+// ShowSaveDialog 显示另存为对话框
+func (app *App) ShowSaveDialog(suggested string) (string, bool) {
+	if app.saveDialog != nil {
+		return app.saveDialog(suggested)
+	}
+	dlg := wui.NewFileSaveDialog()
+	dlg.SetTitle("另存为")
+	dlg.SetInitialPath(suggested)
+	formats := []struct {
+		text   string
+		format imaging.Format
+	}{
+		{"PNG 图片", imaging.FormatPNG},
+		{"JPEG 图片", imaging.FormatJPEG},
+		{"BMP 图片", imaging.FormatBMP},
+		{"WebP 图片（无损）", imaging.FormatWebP},
+	}
+	current, _ := imaging.FormatFromPath(suggested)
+	for i, f := range formats {
+		dlg.AddFilter(f.text, f.format.Ext())
+		if f.format == current {
+			dlg.SetFilterIndex(i)
+		}
+	}
+	ok, path := dlg.Execute(app.window)
+	return path, ok
+}
+
+// Save 保存当前图片，path 可包含 ExpandFilename 占位符，返回实际写入的路径
+func (img *ImageDisplay) Save(path string, opts ...SaveOption) (string, error) {
+	return SaveImage(img.image, path, opts...)
+}
+
+// SaveAs 弹出另存为对话框后保存，用户取消时返回 ErrSaveCancelled
+func (img *ImageDisplay) SaveAs(suggested string, opts ...SaveOption) (string, error) {
+	if img.image == nil {
+		return "", ErrNoImage
+	}
+	path, ok := img.app.ShowSaveDialog(ExpandFilename(suggested, img.image, time.Now()))
+	if !ok {
+		return "", ErrSaveCancelled
+	}
+	// 用户已在对话框中确认覆盖
+	return SaveImage(img.image, path, append(opts, WithOverwrite())...)
+}
@@ -0,0 +1,175 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule 截图计划
+type Schedule interface {
+	// Next 返回 after 之后的下一次触发时间，没有下一次时返回零值
+	Next(after time.Time) time.Time
+}
+
+// intervalSchedule 固定间隔
+type intervalSchedule time.Duration
+
+// Every 每隔 d 触发一次；d 必须大于 0，否则 NewCaptureScheduler 返回错误
+func Every(d time.Duration) Schedule {
+	return intervalSchedule(d)
+}
+
+// Next 间隔不为正时没有下一次，避免调用方空转
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	if s <= 0 {
+		return time.Time{}
+	}
+	return after.Add(time.Duration(s))
+}
+
+// String 返回计划描述
+func (s intervalSchedule) String() string {
+	return "every " + time.Duration(s).String()
+}
+
+// cronSchedule 五段式 cron 表达式：分 时 日 月 周
+type cronSchedule struct {
+	expr                     string
+	minute, hour, dom, month []bool
+	dow                      []bool
+	domAny, dowAny           bool
+}
+
+// cronFields cron 各字段的取值范围
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"分", 0, 59},
+	{"时", 0, 23},
+	{"日", 1, 31},
+	{"月", 1, 12},
+	{"周", 0, 7}, // 0 和 7 都表示周日
+}
+
+// ParseCron 解析五段式 cron 表达式，支持 *、*/n、a-b、a-b/n 与逗号列表，
+// 例如 "*/5 9-18 * * 1-5" 表示工作日 9 点到 18 点每 5 分钟
+func ParseCron(expr string) (Schedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("cron 表达式需要 5 个字段: %q", expr)
+	}
+
+	sets := make([][]bool, len(parts))
+	for i, part := range parts {
+		set, err := parseCronField(part, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("cron %s字段 %q 无效: %w", cronFields[i].name, part, err)
+		}
+		sets[i] = set
+	}
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	return &cronSchedule{
+		expr:   expr,
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: strings.HasPrefix(parts[2], "*"),
+		dowAny: strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+// parseCronField 解析单个字段，返回下标为取值的集合
+func parseCronField(field string, minVal, maxVal int) ([]bool, error) {
+	set := make([]bool, maxVal+1)
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("步长无效")
+			}
+			rangePart, step = item[:i], n
+		}
+
+		lo, hi := minVal, maxVal
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("范围无效")
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return nil, fmt.Errorf("不是数字")
+			}
+			lo, hi = n, n
+		}
+		if lo < minVal || hi > maxVal || lo > hi {
+			return nil, fmt.Errorf("超出范围 %d-%d", minVal, maxVal)
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// cronSearchLimit 查找下一次触发时间的最大跨度
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(cronSearchLimit)
+	for t.Before(limit) {
+		if !s.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches 日与周都有限制时满足其一即可（与标准 cron 一致）
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom, dow := s.dom[t.Day()], s.dow[int(t.Weekday())]
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+// String 返回计划描述
+func (s *cronSchedule) String() string {
+	return "cron " + s.expr
+}
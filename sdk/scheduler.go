@@ -0,0 +1,266 @@
+package sdk
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/package-register/gui/event"
+	"github.com/package-register/gui/imaging"
+)
+
+// 定时截图文件命名
+const (
+	capturePrefix     = "capture_"
+	captureExt        = ".png"
+	captureTimeLayout = "20060102_150405.000"
+)
+
+// 定时截图默认参数
+const (
+	defaultCaptureTolerance = 16  // 像素通道差值超过此值才算变化
+	defaultCaptureMaxFiles  = 500 // 默认最多保留的截图数
+)
+
+// CaptureSchedulerConfig 定时截图配置
+type CaptureSchedulerConfig struct {
+	Schedule  Schedule      // 触发计划，如 Every(10*time.Second) 或 ParseCron("*/5 * * * *")
+	Target    CaptureTarget // 截图目标，默认主显示器
+	Dir       string        // 保存目录
+	MinChange float64       // 与上一张相比变化的像素比例低于此值时跳过，0 表示总是保存
+	Tolerance uint8         // 像素通道差值容差，0 表示默认
+	MaxFiles  int           // 最多保留的截图数，0 表示默认，负数表示不限
+	MaxAge    time.Duration // 截图最长保留时间，0 表示不限
+	Redactor  *Redactor     // 保存前打码
+}
+
+// CaptureEntry 时间线中的一张截图
+type CaptureEntry struct {
+	Path   string
+	Time   time.Time
+	Change float64 // 与上一张相比变化的像素比例
+}
+
+// CaptureScheduler 定时截图器
+type CaptureScheduler struct {
+	config CaptureSchedulerConfig
+	events *event.Bus
+
+	mu        sync.Mutex
+	stop      chan struct{}
+	done      chan struct{}
+	last      image.Image
+	onCapture []func(CaptureEntry)
+}
+
+// NewCaptureScheduler 创建定时截图器
+func NewCaptureScheduler(config CaptureSchedulerConfig) (*CaptureScheduler, error) {
+	if config.Schedule == nil {
+		return nil, fmt.Errorf("未设置截图计划")
+	}
+	if d, ok := config.Schedule.(intervalSchedule); ok && d <= 0 {
+		return nil, fmt.Errorf("截图间隔必须大于 0: %v", time.Duration(d))
+	}
+	if config.Dir == "" {
+		return nil, fmt.Errorf("未设置截图保存目录")
+	}
+	if config.Target == (CaptureTarget{}) {
+		config.Target = CaptureDisplay(0)
+	}
+	if config.Tolerance == 0 {
+		config.Tolerance = defaultCaptureTolerance
+	}
+	if config.MaxFiles == 0 {
+		config.MaxFiles = defaultCaptureMaxFiles
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建截图目录失败: %w", err)
+	}
+	return &CaptureScheduler{config: config}, nil
+}
+
+// Dir 截图保存目录
+func (s *CaptureScheduler) Dir() string {
+	return s.config.Dir
+}
+
+// SetEventBus 设置截图事件发布的事件总线
+func (s *CaptureScheduler) SetEventBus(bus *event.Bus) {
+	s.mu.Lock()
+	s.events = bus
+	s.mu.Unlock()
+}
+
+// OnCapture 订阅新保存的截图
+func (s *CaptureScheduler) OnCapture(handler func(CaptureEntry)) {
+	s.mu.Lock()
+	s.onCapture = append(s.onCapture, handler)
+	s.mu.Unlock()
+}
+
+// Running 是否正在运行
+func (s *CaptureScheduler) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stop != nil
+}
+
+// Start 开始定时截图，重复调用无效
+func (s *CaptureScheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.loop(s.stop, s.done)
+}
+
+// Stop 停止定时截图并等待当前截图完成
+func (s *CaptureScheduler) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+func (s *CaptureScheduler) loop(stop, done chan struct{}) {
+	defer close(done)
+	for {
+		now := time.Now()
+		next := s.config.Schedule.Next(now)
+		if next.IsZero() {
+			log.Printf("Capture schedule has no next run, stopping")
+			return
+		}
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if _, err := s.CaptureNow(); err != nil {
+			log.Printf("Scheduled capture failed: %v", err)
+		}
+	}
+}
+
+// CaptureNow 立即截图一次，画面变化不足时返回 nil
+//
+// 无论是否保存都会按保留策略清理旧截图，画面长时间不变时 MaxAge 同样生效。
+func (s *CaptureScheduler) CaptureNow() (*CaptureEntry, error) {
+	defer s.prune()
+	shot, err := Capture(s.config.Target)
+	if err != nil {
+		return nil, err
+	}
+	var img image.Image = shot
+	if s.config.Redactor != nil {
+		if img, err = s.config.Redactor.Redact(img); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	last := s.last
+	s.mu.Unlock()
+
+	change := 1.0
+	if last != nil {
+		change = imaging.ChangeRatio(last, img, s.config.Tolerance)
+		if change < s.config.MinChange {
+			return nil, nil
+		}
+	}
+
+	entry := CaptureEntry{Time: shot.Time, Change: change}
+	entry.Path = filepath.Join(s.config.Dir, capturePrefix+shot.Time.Format(captureTimeLayout)+captureExt)
+	// 文件名即时间线时间戳，不能被自动改名
+	if _, err := SaveImage(img, entry.Path, WithFormat(imaging.FormatPNG), WithOverwrite()); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.last = img
+	handlers := s.onCapture
+	events := s.events
+	s.mu.Unlock()
+
+	if events != nil {
+		events.Emit(event.ScheduledCapture, entry)
+	}
+	for _, h := range handlers {
+		h(entry)
+	}
+	return &entry, nil
+}
+
+// Entries 目录中的所有截图，按时间先后排序
+func (s *CaptureScheduler) Entries() ([]CaptureEntry, error) {
+	files, err := os.ReadDir(s.config.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []CaptureEntry
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasPrefix(name, capturePrefix) || !strings.HasSuffix(name, captureExt) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, capturePrefix), captureExt)
+		t, err := time.ParseInLocation(captureTimeLayout, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, CaptureEntry{Path: filepath.Join(s.config.Dir, name), Time: t})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	return entries, nil
+}
+
+// prune 按数量与时间清理旧截图
+func (s *CaptureScheduler) prune() {
+	entries, err := s.Entries()
+	if err != nil {
+		log.Printf("Capture retention scan failed: %v", err)
+		return
+	}
+	cutoff := time.Time{}
+	if s.config.MaxAge > 0 {
+		cutoff = time.Now().Add(-s.config.MaxAge)
+	}
+	excess := 0
+	if s.config.MaxFiles > 0 && len(entries) > s.config.MaxFiles {
+		excess = len(entries) - s.config.MaxFiles
+	}
+	for i, e := range entries {
+		if i < excess || e.Time.Before(cutoff) {
+			if err := os.Remove(e.Path); err != nil {
+				log.Printf("Remove old capture %s failed: %v", e.Path, err)
+			}
+		}
+	}
+}
+
+// LoadCapture 读取时间线中的截图
+func LoadCapture(entry CaptureEntry) (image.Image, error) {
+	f, err := os.Open(entry.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
@@ -0,0 +1,313 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+
+	w32 "github.com/gonutz/w32/v2"
+
+	"github.com/package-register/gui/event"
+)
+
+// 内置快捷键动作名称，可通过 WithKeymap 重新绑定
+const (
+	ShortcutNextTab    = "tab.next"
+	ShortcutPrevTab    = "tab.prev"
+	ShortcutHideWindow = "window.hide"
+	ShortcutFocusChat  = "chat.focus"
+	ShortcutCheatsheet = "help.shortcuts"
+)
+
+// ShortcutJumpTab 跳转到第 n 个 Tab（1~9）的动作名称
+func ShortcutJumpTab(n int) string {
+	return fmt.Sprintf("tab.%d", n)
+}
+
+// DefaultKeymap 内置动作的默认键位
+func DefaultKeymap() map[string]string {
+	keymap := map[string]string{
+		ShortcutNextTab:    "Ctrl+Tab",
+		ShortcutPrevTab:    "Ctrl+Shift+Tab",
+		ShortcutHideWindow: "Esc",
+		ShortcutFocusChat:  "Ctrl+L",
+		ShortcutCheatsheet: "F1",
+	}
+	for n := 1; n <= 9; n++ {
+		keymap[ShortcutJumpTab(n)] = fmt.Sprintf("Ctrl+%d", n)
+	}
+	return keymap
+}
+
+// Shortcut 窗口内快捷键
+type Shortcut struct {
+	Name        string
+	Description string
+	Accelerator Accelerator // 零值表示未绑定
+	Tab         string      // 所属 Tab，空表示应用级
+
+	action func()
+}
+
+// Bound 是否绑定了组合键
+func (s Shortcut) Bound() bool {
+	return s.Accelerator.Key != 0
+}
+
+// WithKeymap 按动作名称覆盖快捷键键位，如 {"tab.next": "Ctrl+PageDown"}；
+// 值为空字符串表示取消绑定。对内置动作和之后通过 AddShortcut 注册的动作都生效。
+func WithKeymap(keymap map[string]string) Option {
+	return func(a *App) {
+		if a.keymap == nil {
+			a.keymap = make(map[string]string)
+		}
+		for name, accelerator := range keymap {
+			a.keymap[name] = accelerator
+		}
+	}
+}
+
+// LoadKeymap 从 JSON 配置读取键位，格式为 {"动作名称": "组合键"}
+func LoadKeymap(r io.Reader) (map[string]string, error) {
+	var keymap map[string]string
+	if err := json.NewDecoder(r).Decode(&keymap); err != nil {
+		return nil, fmt.Errorf("解析键位配置失败: %w", err)
+	}
+	for name, accelerator := range keymap {
+		if accelerator == "" {
+			continue
+		}
+		if _, err := ParseAccelerator(accelerator); err != nil {
+			return nil, fmt.Errorf("动作 %s: %w", name, err)
+		}
+	}
+	return keymap, nil
+}
+
+// AddShortcut 注册应用级快捷键，窗口在前台时任意 Tab 下都有效
+//
+// accelerator 会被 WithKeymap 中同名动作的键位覆盖。
+func (app *App) AddShortcut(name, description, accelerator string, action func()) error {
+	_, err := app.addShortcut("", name, description, accelerator, action)
+	return err
+}
+
+// AddShortcut 注册只在本 Tab 激活时有效的快捷键，与应用级快捷键冲突时优先
+func (t *TabContext) AddShortcut(name, description, accelerator string, action func()) error {
+	_, err := t.app.addShortcut(t.name, name, description, accelerator, action)
+	return err
+}
+
+func (app *App) addShortcut(tab, name, description, accelerator string, action func()) (*Shortcut, error) {
+	if override, ok := app.keymap[name]; ok {
+		accelerator = override
+	}
+	s := &Shortcut{Name: name, Description: description, Tab: tab, action: action}
+	if accelerator != "" {
+		acc, err := ParseAccelerator(accelerator)
+		if err != nil {
+			return nil, err
+		}
+		s.Accelerator = acc
+	}
+	for _, existing := range app.shortcuts {
+		if existing.Tab != tab {
+			continue
+		}
+		if existing.Name == name {
+			return nil, fmt.Errorf("快捷键动作 %s 已存在", name)
+		}
+		if s.Bound() && existing.Accelerator == s.Accelerator {
+			return nil, fmt.Errorf("%s 已绑定到 %s: %w", s.Accelerator, existing.Name, ErrHotkeyConflict)
+		}
+	}
+	app.shortcuts = append(app.shortcuts, s)
+	return s, nil
+}
+
+// Shortcuts 当前 Tab 下有效的快捷键：本 Tab 的在前，其后是未被遮蔽的应用级快捷键
+func (app *App) Shortcuts() []Shortcut {
+	var out []Shortcut
+	shadowed := make(map[Accelerator]bool)
+	for _, s := range app.shortcuts {
+		if s.Tab != "" && s.Tab == app.activeTab {
+			out = append(out, *s)
+			shadowed[s.Accelerator] = true
+		}
+	}
+	for _, s := range app.shortcuts {
+		if s.Tab == "" && !(s.Bound() && shadowed[s.Accelerator]) {
+			out = append(out, *s)
+		}
+	}
+	return out
+}
+
+// TriggerShortcut 执行与组合键匹配的快捷键，返回是否有快捷键响应
+func (app *App) TriggerShortcut(acc Accelerator) bool {
+	for _, s := range app.Shortcuts() {
+		if s.Bound() && s.Accelerator == acc && s.action != nil {
+			s.action()
+			app.events.Emit(event.ShortcutTriggered, s.Name)
+			return true
+		}
+	}
+	return false
+}
+
+// registerBuiltinShortcuts 注册内置动作
+func (app *App) registerBuiltinShortcuts() {
+	type builtin struct {
+		name, description string
+		action            func()
+	}
+	defaults := DefaultKeymap()
+	builtins := []builtin{
+		{ShortcutNextTab, "下一个 Tab", func() { app.cycleTab(1) }},
+		{ShortcutPrevTab, "上一个 Tab", func() { app.cycleTab(-1) }},
+		{ShortcutHideWindow, "关闭快捷键列表或隐藏窗口（无托盘时最小化）", app.escape},
+		{ShortcutFocusChat, "聚焦聊天输入框", app.FocusChat},
+		{ShortcutCheatsheet, "显示/关闭快捷键列表", app.ToggleShortcuts},
+	}
+	for n := 1; n <= 9; n++ {
+		index := n - 1
+		builtins = append(builtins, builtin{ShortcutJumpTab(n), fmt.Sprintf("切换到第 %d 个 Tab", n), func() { app.jumpTab(index) }})
+	}
+	for _, b := range builtins {
+		if err := app.AddShortcut(b.name, b.description, defaults[b.name], b.action); err != nil {
+			log.Printf("Builtin shortcut %s disabled: %v", b.name, err)
+		}
+	}
+}
+
+// cycleTab 按注册顺序切换到相邻的 Tab
+func (app *App) cycleTab(step int) {
+	n := len(app.tabOrder)
+	if n == 0 {
+		return
+	}
+	cur := 0
+	for i, name := range app.tabOrder {
+		if name == app.activeTab {
+			cur = i
+		}
+	}
+	app.jumpTab(((cur+step)%n + n) % n)
+}
+
+// jumpTab 切换到第 index 个 Tab（从 0 开始）
+func (app *App) jumpTab(index int) {
+	if index >= 0 && index < len(app.tabOrder) {
+		app.SwitchTab(app.tabOrder[index])
+	}
+}
+
+// escape 快捷键列表打开时关闭列表；否则有托盘图标时隐藏窗口，没有时最小化，
+// 避免窗口隐藏后无处找回
+func (app *App) escape() {
+	if app.cheatsheet != nil && app.cheatsheet.visible {
+		app.HideShortcuts()
+		return
+	}
+	if app.trayEnabled {
+		app.HideWindow()
+	} else if app.window != nil && app.window.Handle() != 0 {
+		w32.ShowWindow(w32.HWND(app.window.Handle()), w32.SW_MINIMIZE)
+	}
+}
+
+// FocusChat 聚焦聊天输入框：优先当前 Tab 中的，否则切换到第一个含聊天面板的 Tab
+func (app *App) FocusChat() {
+	var target *ChatPanel
+	for _, c := range app.chatPanels {
+		if c.tab != nil && c.tab.name == app.activeTab {
+			target = c
+			break
+		}
+		if target == nil {
+			target = c
+		}
+	}
+	if target == nil {
+		return
+	}
+	if target.tab == nil {
+		target.input.Focus()
+		return
+	}
+	app.SwitchTab(target.tab.name)
+	target.tab.Focus(target.input)
+}
+
+// focusedChat 输入框获得焦点的聊天面板
+func (app *App) focusedChat() *ChatPanel {
+	focus := uintptr(w32.GetFocus())
+	for _, c := range app.chatPanels {
+		if focus != 0 && c.input.Handle() == focus {
+			return c
+		}
+	}
+	return nil
+}
+
+// currentModifiers 当前按下的修饰键
+func currentModifiers() Modifiers {
+	var mods Modifiers
+	pressed := func(key int) bool { return w32.GetKeyState(key)&0x8000 != 0 }
+	if pressed(w32.VK_CONTROL) {
+		mods |= ModCtrl
+	}
+	if pressed(w32.VK_MENU) {
+		mods |= ModAlt
+	}
+	if pressed(w32.VK_SHIFT) {
+		mods |= ModShift
+	}
+	if pressed(w32.VK_LWIN) || pressed(w32.VK_RWIN) {
+		mods |= ModWin
+	}
+	return mods
+}
+
+// isModifierKey 单独按下修饰键不触发快捷键
+func isModifierKey(key int) bool {
+	switch key {
+	case w32.VK_CONTROL, w32.VK_MENU, w32.VK_SHIFT, w32.VK_LWIN, w32.VK_RWIN,
+		w32.VK_LCONTROL, w32.VK_RCONTROL, w32.VK_LMENU, w32.VK_RMENU, w32.VK_LSHIFT, w32.VK_RSHIFT:
+		return true
+	}
+	return false
+}
+
+// handleKey 处理一次按键：Tab 键导航、聊天输入框中回车发送，其余交给快捷键表；返回是否已处理
+func (app *App) handleKey(key int, mods Modifiers) bool {
+	if isModifierKey(key) {
+		return false
+	}
+	if key == w32.VK_TAB && app.handleTabKey(mods) {
+		return true
+	}
+	if key == w32.VK_RETURN && mods == 0 {
+		if c := app.focusedChat(); c != nil {
+			c.SendInput()
+			return true
+		}
+	}
+	return app.TriggerShortcut(Accelerator{Mods: mods, Key: key})
+}
+
+// keyboardHook 线程键盘钩子，只在主窗口位于前台时处理按下的键
+func (app *App) keyboardHook(code int, wParam w32.WPARAM, lParam w32.LPARAM) w32.LRESULT {
+	const (
+		hcAction  = 0
+		keyUpFlag = 1 << 31
+	)
+	if code == hcAction && lParam&keyUpFlag == 0 && app.window != nil &&
+		w32.GetForegroundWindow() == w32.HWND(app.window.Handle()) {
+		if app.handleKey(int(wParam), currentModifiers()) {
+			return 1 // 吞掉按键，不再交给控件
+		}
+	}
+	return w32.CallNextHookEx(app.keyHook, code, wParam, lParam)
+}
@@ -1,6 +1,9 @@
 package sdk
 
 import (
+	"unsafe"
+
+	w32 "github.com/gonutz/w32/v2"
 	"github.com/gonutz/wui/v2"
 )
 
@@ -32,6 +35,9 @@ type Theme struct {
 	// 边框
 	BorderWidth   int
 	CornerRadius  int
+
+	// 无障碍
+	MinTargetSize int // 可交互控件的最小宽高，Audit 据此检查
 }
 
 // DefaultTheme 默认现代主题（Material Design 风格）
@@ -63,6 +69,8 @@ func DefaultTheme() *Theme {
 		// 边框
 		BorderWidth:   1,
 		CornerRadius:  4,
+
+		MinTargetSize: 24,
 	}
 }
 
@@ -91,7 +99,57 @@ func DarkTheme() *Theme {
 
 		BorderWidth:   1,
 		CornerRadius:  4,
+
+		MinTargetSize: 24,
+	}
+}
+
+// HighContrastTheme 高对比度主题：黑底白字、黄色强调色，字号与最小点击尺寸更大
+func HighContrastTheme() *Theme {
+	return &Theme{
+		Background: wui.RGB(0, 0, 0),       // #000000
+		Surface:    wui.RGB(0, 0, 0),       // #000000
+		Foreground: wui.RGB(255, 255, 255), // #FFFFFF
+		Primary:    wui.RGB(255, 255, 0),   // #FFFF00
+		Secondary:  wui.RGB(0, 255, 255),   // #00FFFF
+		Accent:     wui.RGB(255, 255, 0),   // #FFFF00
+		Error:      wui.RGB(255, 128, 128), // #FF8080
+		Border:     wui.RGB(255, 255, 255), // #FFFFFF
+
+		DefaultFont: "微软雅黑",
+		HeadingFont: "微软雅黑",
+		MonoFont:    "Consolas",
+		FontSize:    -16,
+
+		XSmallPadding: 4,
+		SmallPadding:  8,
+		MediumPadding: 16,
+		LargePadding:  24,
+		XLargePadding: 32,
+
+		BorderWidth:  2,
+		CornerRadius: 0,
+
+		MinTargetSize: 32,
+	}
+}
+
+// SystemHighContrast 系统是否开启了高对比度模式
+func SystemHighContrast() bool {
+	const (
+		spiGetHighContrast = 0x0042
+		hcfHighContrastOn  = 0x1
+	)
+	var hc struct {
+		size          uint32
+		flags         uint32
+		defaultScheme *uint16
+	}
+	hc.size = uint32(unsafe.Sizeof(hc))
+	if !w32.SystemParametersInfo(spiGetHighContrast, uint(hc.size), uintptr(unsafe.Pointer(&hc)), 0) {
+		return false
 	}
+	return hc.flags&hcfHighContrastOn != 0
 }
 
 // ApplyToPanel 将主题应用到面板
@@ -198,6 +256,17 @@ func WithTheme(theme *Theme) Option {
 	}
 }
 
+// Theme 当前主题：WithTheme 设置的主题；未设置时系统开启高对比度则为 HighContrastTheme，否则为 DefaultTheme
+func (app *App) Theme() *Theme {
+	if app.theme != nil {
+		return app.theme
+	}
+	if SystemHighContrast() {
+		return HighContrastTheme()
+	}
+	return DefaultTheme()
+}
+
 // GetPadding 获取指定级别的内边距
 func (t *Theme) GetPadding(level int) int {
 	switch level {
@@ -4,12 +4,12 @@ import (
 	"fmt"
 	"github.com/package-register/gui/event"
 	"image"
-	"image/png"
-	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gonutz/wui/v2"
-	"github.com/kbinani/screenshot"
+	"github.com/package-register/gui/imaging"
 )
 
 // ScreenshotCallback 截图回调函数
@@ -21,6 +21,14 @@ type TabContext struct {
 	panel  *wui.Panel
 	app    *App
 	events *event.Bus
+
+	// 焦点
+	tabIndex     map[wui.Control]int // 显式的 Tab 键顺序
+	defaultFocus wui.Control
+	lastFocus    wui.Control
+
+	// 无障碍
+	access map[wui.Control]*Accessibility
 }
 
 // Name 获取Tab名称
@@ -108,83 +116,36 @@ func (t *TabContext) AddImage(x, y, w, h int) *ImageDisplay {
 	paintBox.SetBounds(x, y, w, h)
 
 	img := &ImageDisplay{
-		paintBox: paintBox,
-		wuiImage: nil,
-		x:        x,
-		y:        y,
-		width:    w,
-		height:   h,
-		image:    nil,
+		paintBox:  paintBox,
+		x:         x,
+		y:         y,
+		width:     w,
+		height:    h,
+		image:     nil,
+		app:       t.app,
+		view:      imaging.NewView(image.Point{}, image.Pt(w, h)),
+		panButton: noMouseButton,
 	}
 
 	// 设置绘制回调
-	paintBox.SetOnPaint(func(canvas *wui.Canvas) {
-		if img.image != nil && img.wuiImage != nil {
-			// 清空背景
-			canvas.FillRect(0, 0, w, h, wui.RGB(240, 240, 240))
-
-			// 获取原始图片尺寸
-			srcBounds := img.image.Bounds()
-			srcWidth := srcBounds.Dx()
-			srcHeight := srcBounds.Dy()
-
-			// 如果图片比显示区域小，直接居中显示
-			if srcWidth <= w && srcHeight <= h {
-				offsetX := (w - srcWidth) / 2
-				offsetY := (h - srcHeight) / 2
-				srcRect := wui.Rect(0, 0, srcWidth, srcHeight)
-				canvas.DrawImage(img.wuiImage, srcRect, offsetX, offsetY)
-				return
-			}
-
-			// 计算缩放比例，保持宽高比
-			scaleX := float64(w) / float64(srcWidth)
-			scaleY := float64(h) / float64(srcHeight)
-			scale := scaleX
-			if scaleY < scaleX {
-				scale = scaleY
-			}
+	paintBox.SetOnPaint(img.paint)
 
-			// 计算显示尺寸和居中位置
-			displayWidth := int(float64(srcWidth) * scale)
-			displayHeight := int(float64(srcHeight) * scale)
-			offsetX := (w - displayWidth) / 2
-			offsetY := (h - displayHeight) / 2
-
-			// 绘制图片
-			srcRect := wui.Rect(0, 0, srcWidth, srcHeight)
-			canvas.DrawImage(img.wuiImage, srcRect, offsetX, offsetY)
-
-			// 绘制边框
-			canvas.DrawRect(offsetX-1, offsetY-1, displayWidth+2, displayHeight+2, wui.RGB(100, 100, 100))
-		} else {
-			// 没有图片时显示占位符
-			canvas.FillRect(0, 0, w, h, wui.RGB(200, 200, 200))
-			canvas.TextRect(0, 0, w, h, "暂无图片", wui.RGB(0, 0, 0))
-		}
-	})
-
-	// 添加鼠标移动事件处理（用于提示点击）
-	paintBox.SetOnMouseMove(func(mouseX, mouseY int) {
-		if img.image != nil {
-			img.isHover = true
-		} else {
-			img.isHover = false
-		}
-	})
+	// 鼠标事件：滚轮缩放、拖拽平移、单击；标注编辑中左右键交给编辑器
+	t.HandleMouse(paintBox, img.mouseHandler())
+	t.SetContextMenuFunc(paintBox, img.contextMenu)
 
 	t.panel.Add(paintBox)
 	return img
 }
 
-// AddScreenshotButton 添加截图按钮
-func (t *TabContext) AddScreenshotButton(text string, x, y, w, h int, hideWindow bool, callback ScreenshotCallback) *wui.Button {
+// AddScreenshotButton 添加截图按钮，可通过 opts 指定截图目标等选项
+func (t *TabContext) AddScreenshotButton(text string, x, y, w, h int, hideWindow bool, callback ScreenshotCallback, opts ...ScreenshotOption) *wui.Button {
 	btn := wui.NewButton()
 	btn.SetText(text)
 	btn.SetBounds(x, y, w, h)
 
 	btn.SetOnClick(func() {
-		t.takeScreenshot(hideWindow, callback)
+		t.takeScreenshot(hideWindow, callback, opts...)
 	})
 
 	t.panel.Add(btn)
@@ -192,7 +153,9 @@ func (t *TabContext) AddScreenshotButton(text string, x, y, w, h int, hideWindow
 }
 
 // takeScreenshot 执行截图
-func (t *TabContext) takeScreenshot(hideWindow bool, callback ScreenshotCallback) {
+func (t *TabContext) takeScreenshot(hideWindow bool, callback ScreenshotCallback, opts ...ScreenshotOption) {
+	cfg := newScreenshotConfig(opts)
+
 	// 如果需要隐藏窗口
 	if hideWindow && t.app.window != nil {
 		originalVisible := t.app.visible
@@ -205,7 +168,7 @@ func (t *TabContext) takeScreenshot(hideWindow bool, callback ScreenshotCallback
 		// 延迟截图，确保窗口已隐藏
 		go func() {
 			time.Sleep(100 * time.Millisecond)
-			img, err := t.captureScreen()
+			img, err := t.captureScreen(cfg)
 
 			// 恢复窗口显示
 			if originalVisible {
@@ -220,7 +183,7 @@ func (t *TabContext) takeScreenshot(hideWindow bool, callback ScreenshotCallback
 	} else {
 		// 直接截图
 		go func() {
-			img, err := t.captureScreen()
+			img, err := t.captureScreen(cfg)
 			if callback != nil {
 				callback(img, err)
 			}
@@ -229,42 +192,106 @@ func (t *TabContext) takeScreenshot(hideWindow bool, callback ScreenshotCallback
 }
 
 // captureScreen 捕获屏幕
-func (t *TabContext) captureScreen() (image.Image, error) {
-	// 获取主显示器
-	bounds := screenshot.GetDisplayBounds(0)
-	img, err := screenshot.CaptureRect(bounds)
+func (t *TabContext) captureScreen(cfg *screenshotConfig) (image.Image, error) {
+	img, err := Capture(cfg.target)
 	if err != nil {
 		return nil, err
 	}
+	if cfg.selectRegion {
+		if img, err = t.app.SelectRegion(img); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.redactor != nil {
+		if img, err = cfg.redactor.Redact(img); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.copyImage {
+		t.copyScreenshot(img)
+	}
+	if cfg.copyText {
+		t.copyScreenshotText(cfg, img)
+	}
 	return img, nil
 }
 
-// ImageDisplay 图片显示组件
+// ScreenshotOption 截图选项
+type ScreenshotOption func(*screenshotConfig)
+
+// screenshotConfig 截图配置
+type screenshotConfig struct {
+	target       CaptureTarget
+	selectRegion bool
+	redactor     *Redactor
+	copyText     bool
+	recognizer   TextRecognizer
+	copyImage    bool
+}
+
+func newScreenshotConfig(opts []ScreenshotOption) *screenshotConfig {
+	cfg := &screenshotConfig{target: CaptureDisplay(0)} // 默认主显示器
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithCaptureTarget 指定截图目标（显示器、全部显示器或屏幕矩形）
+func WithCaptureTarget(target CaptureTarget) ScreenshotOption {
+	return func(c *screenshotConfig) { c.target = target }
+}
+
+// ImageDisplay 图片显示组件，支持滚轮缩放、拖拽平移与像素信息
 type ImageDisplay struct {
 	paintBox *wui.PaintBox
-	wuiImage *wui.Image
 	x        int
 	y        int
 	width    int
 	height   int
 	image    image.Image
+	rgba     *image.RGBA
 	mouseX   int
 	mouseY   int
 	isHover  bool
 	onClick  func()
+	editor   *AnnotationEditor
+	app      *App
+
+	view      *imaging.View
+	renderer  imaging.Renderer
+	buffer    *image.RGBA // 按视图渲染好的显示内容
+	bits      []byte      // 绘制到窗口用的 BGRA 缓冲
+	dirty     bool
+	downX     int
+	downY     int
+	dragged   bool
+	panButton wui.MouseButton
+	pixel     PixelInfo
+	onPixel   func(PixelInfo)
+	hidePixel bool
+}
+
+// SetImage 设置图片，启用标注编辑时会清空已有标注
+func (img *ImageDisplay) SetImage(image image.Image) {
+	if img.editor != nil {
+		img.editor.reset(image)
+	}
+	img.setImage(image)
 }
 
-// SetImage 设置图片
-func (img *ImageDisplay) SetImage(image image.Image) {
+// setImage 更新显示的图片，尺寸不变时保持当前缩放与位置
+func (img *ImageDisplay) setImage(image image.Image) {
 	img.image = image
 	if image != nil {
-		// 转换为wui.Image
-		img.wuiImage = wui.NewImage(image)
+		img.rgba = rgbaOf(image)
+		img.view.SetImageSize(image.Bounds().Size())
 	} else {
-		img.wuiImage = nil
+		img.rgba = nil
 	}
+	img.renderer.Reset()
 	// 触发重绘
-	img.paintBox.Paint()
+	img.invalidate()
 }
 
 // SetOnClick 设置点击回调
@@ -277,19 +304,10 @@ func (img *ImageDisplay) GetImage() image.Image {
 	return img.image
 }
 
-// SaveToFile 保存图片到文件
+// SaveToFile 保存图片到文件，格式按扩展名推断，已存在时覆盖
 func (img *ImageDisplay) SaveToFile(filename string) error {
-	if img.image == nil {
-		return nil
-	}
-
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	return png.Encode(file, img.image)
+	_, err := SaveImage(img.image, filename, WithOverwrite())
+	return err
 }
 
 // Panel 获取底层Panel（高级用法）
@@ -349,11 +367,19 @@ func (t *TabContext) AddChatPanel(x, y, w, h int) *ChatPanel {
 	sendBtn.SetBounds(btnX, inputY, buttonHeight*2, inputHeight) // 稍微宽一点的按钮
 	panel.Add(sendBtn)
 
+	// 工具调用审批栏（覆盖在输入区域上，默认隐藏）
+	approval := newApprovalBar(t.app, padding, inputY, w-padding*2, inputHeight)
+	panel.Add(approval.panel)
+
 	chatPanel := &ChatPanel{
 		panel:      panel,
 		history:    historyEdit,
 		input:      inputEdit,
 		sendBtn:    sendBtn,
+		approval:   approval,
+		events:     t.events,
+		app:        t.app,
+		tab:        t,
 		aiService:  nil,
 		onSend:     nil,
 		onReceive:  nil,
@@ -366,8 +392,12 @@ func (t *TabContext) AddChatPanel(x, y, w, h int) *ChatPanel {
 		}
 	})
 
-	// 注册输入框到应用，用于回车键支持
-	t.app.registerChatInput(inputEdit, chatPanel)
+	// 注册到应用，用于回车发送与 Ctrl+L 聚焦
+	t.app.registerChatInput(chatPanel)
+
+	t.SetAccessible(historyEdit, "聊天记录", "")
+	t.SetAccessible(inputEdit, "聊天输入", "按回车发送")
+	t.SetContextMenuFunc(historyEdit, chatPanel.contextMenu)
 
 	t.panel.Add(panel)
 	return chatPanel
@@ -379,14 +409,30 @@ type ChatPanel struct {
 	history   *wui.TextEdit
 	input     *wui.EditLine
 	sendBtn   *wui.Button
+	approval  *approvalBar
+	events    *event.Bus
+	app       *App
+	tab       *TabContext
 	aiService *AIService
 	onSend    func()
 	onReceive  func(message string)
+
+	// 结构化消息记录（用于导出）
+	mu          sync.Mutex
+	messages    []ChatMessage
+	attachments []image.Image // 待随下一条用户消息发送的附件
+	redactor    *Redactor     // 附件打码管道
 }
 
 // SetAIService 设置 AI 服务
 func (c *ChatPanel) SetAIService(aiService *AIService) {
 	c.aiService = aiService
+	if aiService != nil {
+		aiService.SetApprover(c.approveToolCall)
+		if c.events != nil {
+			aiService.SetEventBus(c.events)
+		}
+	}
 }
 
 // OnSend 设置发送回调
@@ -405,8 +451,19 @@ func (c *ChatPanel) SendMessage(message string) {
 		return
 	}
 
+	// 斜杠命令
+	if strings.HasPrefix(message, "/") && c.runCommand(message) {
+		c.input.SetText("")
+		return
+	}
+
 	// 显示用户消息
+	c.mu.Lock()
+	attachments := c.attachments
+	c.attachments = nil
+	c.mu.Unlock()
 	c.appendMessage("用户", message)
+	c.record(ChatRoleUser, message, attachments)
 
 	// 清空输入框
 	c.input.SetText("")
@@ -414,6 +471,8 @@ func (c *ChatPanel) SendMessage(message string) {
 	// 如果有 AI 服务，调用 AI
 	if c.aiService != nil {
 		go func() {
+			c.app.replyStarted()
+
 			// 显示"正在生成"提示
 			c.appendSystemMessage("AI 正在生成回复...")
 
@@ -425,8 +484,10 @@ func (c *ChatPanel) SendMessage(message string) {
 			aiStartPos := len(c.history.Text())
 
 			// 调用 AI 流式接口
+			var reply strings.Builder
 			err := c.aiService.ChatStream(message, func(chunk string) {
 				// 追加新的内容块
+				reply.WriteString(chunk)
 				currentText := c.history.Text()
 				c.history.SetText(currentText + chunk)
 			})
@@ -436,12 +497,15 @@ func (c *ChatPanel) SendMessage(message string) {
 				fullText := c.history.Text()
 				c.history.SetText(fullText[:aiStartPos])
 				c.appendSystemMessage("❌ AI 调用失败: " + err.Error())
+				c.app.replyFinished(err)
 				return
 			}
 
 			// 添加换行
 			currentText = c.history.Text()
 			c.history.SetText(currentText + "\n\n")
+			c.record(ChatRoleAssistant, reply.String(), nil)
+			c.app.replyFinished(nil)
 
 			// 触发接收回调
 			finalText := c.history.Text()
@@ -470,6 +534,8 @@ func (c *ChatPanel) appendMessage(role, message string) {
 }
 
 // appendSystemMessage 添加系统消息
+//
+// 系统消息是“正在生成”、附件与导出结果等临时状态，只显示不记录，不会出现在导出的对话中。
 func (c *ChatPanel) appendSystemMessage(message string) {
 	currentText := c.history.Text()
 	timestamp := getCurrentTime()
@@ -486,6 +552,9 @@ func (c *ChatPanel) GetHistory() string {
 // ClearHistory 清空聊天历史
 func (c *ChatPanel) ClearHistory() {
 	c.history.SetText("")
+	c.mu.Lock()
+	c.messages = nil
+	c.mu.Unlock()
 }
 
 // Panel 获取聊天面板（用于添加到 Tab）
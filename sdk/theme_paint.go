@@ -0,0 +1,111 @@
+package sdk
+
+import (
+	"syscall"
+
+	w32 "github.com/gonutz/w32/v2"
+	"github.com/gonutz/wui/v2"
+)
+
+// themePainter 按主题绘制窗口与控件的颜色和面板边框
+//
+// wui 不支持设置控件颜色：静态文本、复选框与面板本身通过父窗口的 WM_CTLCOLORSTATIC 取色，
+// 文本框通过 WM_CTLCOLOREDIT，因此主窗口与所有面板都要处理这些消息。按钮由系统样式绘制，不受影响。
+type themePainter struct {
+	theme      *Theme
+	background w32.HBRUSH
+	surface    w32.HBRUSH
+	border     w32.HBRUSH
+	proc       uintptr           // 面板的子类化回调
+	bordered   map[w32.HWND]bool // 由主题绘制单线边框的面板
+}
+
+func newThemePainter(theme *Theme) *themePainter {
+	p := &themePainter{
+		theme:      theme,
+		background: w32.CreateSolidBrush(uint32(theme.Background)),
+		surface:    w32.CreateSolidBrush(uint32(theme.Surface)),
+		border:     w32.CreateSolidBrush(uint32(theme.Border)),
+		bordered:   make(map[w32.HWND]bool),
+	}
+	p.proc = syscall.NewCallback(func(window w32.HWND, msg uint32, wParam, lParam, subclassID, refData uintptr) uintptr {
+		if brush := p.controlColor(msg, wParam); brush != 0 {
+			return brush
+		}
+		if msg == w32.WM_PAINT && p.bordered[window] {
+			result := w32.DefSubclassProc(window, msg, wParam, lParam)
+			p.paintBorder(window)
+			return result
+		}
+		return w32.DefSubclassProc(window, msg, wParam, lParam)
+	})
+	return p
+}
+
+// controlColor 处理 WM_CTLCOLOR* 消息：设置文字颜色并返回背景画刷，其他消息返回 0
+func (p *themePainter) controlColor(msg uint32, hdc uintptr) uintptr {
+	var bg wui.Color
+	var brush w32.HBRUSH
+	switch msg {
+	case w32.WM_CTLCOLORSTATIC:
+		bg, brush = p.theme.Background, p.background
+	case w32.WM_CTLCOLOREDIT, w32.WM_CTLCOLORLISTBOX:
+		bg, brush = p.theme.Surface, p.surface
+	default:
+		return 0
+	}
+	dc := w32.HDC(hdc)
+	w32.SetTextColor(dc, w32.COLORREF(p.theme.Foreground))
+	w32.SetBkColor(dc, w32.COLORREF(bg))
+	return uintptr(brush)
+}
+
+// apply 窗口显示后子类化所有面板；单线边框改为按主题的颜色与宽度绘制
+func (p *themePainter) apply(window *wui.Window) {
+	p.applyPanels(window.Children())
+	w32.RedrawWindow(w32.HWND(window.Handle()), nil, 0, w32.RDW_INVALIDATE|w32.RDW_ERASE|w32.RDW_ALLCHILDREN)
+}
+
+func (p *themePainter) applyPanels(children []wui.Control) {
+	for _, c := range children {
+		panel, ok := c.(*wui.Panel)
+		if !ok || panel.Handle() == 0 {
+			continue
+		}
+		hwnd := w32.HWND(panel.Handle())
+		if panel.BorderStyle() == wui.PanelBorderSingleLine && p.theme.BorderWidth > 0 {
+			style := w32.GetWindowLongPtr(hwnd, w32.GWL_STYLE)
+			w32.SetWindowLongPtr(hwnd, w32.GWL_STYLE, style&^w32.WS_BORDER)
+			w32.SetWindowPos(hwnd, 0, 0, 0, 0, 0, w32.SWP_NOMOVE|w32.SWP_NOSIZE|w32.SWP_NOZORDER|w32.SWP_FRAMECHANGED)
+			p.bordered[hwnd] = true
+		}
+		w32.SetWindowSubclass(hwnd, p.proc, 0, 0)
+		p.applyPanels(panel.Children())
+	}
+}
+
+// paintBorder 沿面板客户区内侧绘制 BorderWidth 宽的边框
+func (p *themePainter) paintBorder(hwnd w32.HWND) {
+	r := w32.GetClientRect(hwnd)
+	if r == nil {
+		return
+	}
+	width := int32(p.theme.BorderWidth)
+	dc := w32.GetDC(hwnd)
+	defer w32.ReleaseDC(hwnd, dc)
+	for _, edge := range []w32.RECT{
+		{Left: r.Left, Top: r.Top, Right: r.Right, Bottom: r.Top + width},
+		{Left: r.Left, Top: r.Bottom - width, Right: r.Right, Bottom: r.Bottom},
+		{Left: r.Left, Top: r.Top, Right: r.Left + width, Bottom: r.Bottom},
+		{Left: r.Right - width, Top: r.Top, Right: r.Right, Bottom: r.Bottom},
+	} {
+		w32.FillRect(dc, &edge, p.border)
+	}
+}
+
+// close 释放画刷，窗口关闭后调用
+func (p *themePainter) close() {
+	for _, brush := range []w32.HBRUSH{p.background, p.surface, p.border} {
+		w32.DeleteObject(w32.HGDIOBJ(brush))
+	}
+}
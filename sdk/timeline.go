@@ -0,0 +1,122 @@
+package sdk
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gonutz/wui/v2"
+)
+
+// TimelineViewer 定时截图时间线：左侧为截图列表，右侧为所选截图
+type TimelineViewer struct {
+	list      *wui.StringList
+	display   *ImageDisplay
+	info      *wui.Label
+	toggleBtn *wui.Button
+	scheduler *CaptureScheduler
+	entries   []CaptureEntry
+}
+
+// AddTimeline 添加定时截图时间线
+func (t *TabContext) AddTimeline(scheduler *CaptureScheduler, x, y, w, h int) *TimelineViewer {
+	const padding = 8
+	const buttonHeight = 30
+	const listWidth = 220
+
+	v := &TimelineViewer{scheduler: scheduler}
+
+	v.list = wui.NewStringList()
+	v.list.SetBounds(x, y, listWidth, h-buttonHeight-padding)
+	v.list.SetOnChange(func(int) { v.showSelected() })
+	t.panel.Add(v.list)
+	t.SetAccessible(v.list, "截图记录", "")
+
+	buttonWidth := (listWidth - padding) / 2
+	v.toggleBtn = t.AddButton("开始", x, y+h-buttonHeight, buttonWidth, buttonHeight, v.toggle)
+	t.AddButton("刷新", x+buttonWidth+padding, y+h-buttonHeight, buttonWidth, buttonHeight, v.Refresh)
+
+	imageX := x + listWidth + padding
+	v.display = t.AddImage(imageX, y, w-listWidth-padding, h-buttonHeight)
+	v.info = t.AddLabel("", imageX, y+h-buttonHeight+6, w-listWidth-padding, 24)
+
+	scheduler.OnCapture(func(CaptureEntry) { v.Refresh() })
+	v.Refresh()
+	return v
+}
+
+// RegisterTimelineTab 注册内置的定时截图时间线Tab
+func (app *App) RegisterTimelineTab(name string, scheduler *CaptureScheduler) {
+	scheduler.SetEventBus(app.events)
+	app.RegisterTab(name, func(t *TabContext) {
+		t.AddTimeline(scheduler, 10, 10, app.width-20, app.height-app.contentY-20)
+	})
+}
+
+// Display 时间线使用的图片显示组件
+func (v *TimelineViewer) Display() *ImageDisplay {
+	return v.display
+}
+
+// toggle 开始/停止定时截图
+func (v *TimelineViewer) toggle() {
+	if v.scheduler.Running() {
+		v.scheduler.Stop()
+	} else {
+		v.scheduler.Start()
+	}
+	v.updateToggle()
+}
+
+func (v *TimelineViewer) updateToggle() {
+	if v.scheduler.Running() {
+		v.toggleBtn.SetText("停止")
+	} else {
+		v.toggleBtn.SetText("开始")
+	}
+}
+
+// Refresh 重新扫描截图目录；之前选中最新一张时自动跟随新截图
+func (v *TimelineViewer) Refresh() {
+	entries, err := v.scheduler.Entries()
+	if err != nil {
+		v.info.SetText(fmt.Sprintf("读取截图目录失败: %v", err))
+		return
+	}
+
+	selected := v.list.SelectedIndex()
+	followLatest := selected < 0 || selected == len(v.entries)-1
+
+	v.entries = entries
+	items := make([]string, len(entries))
+	for i, e := range entries {
+		items[i] = e.Time.Format("01-02 15:04:05")
+	}
+	v.list.SetItems(items)
+	if followLatest || selected >= len(items) {
+		selected = len(items) - 1
+	}
+	v.list.SetSelectedIndex(selected)
+	v.updateToggle()
+	v.showSelected()
+}
+
+// showSelected 显示所选截图
+func (v *TimelineViewer) showSelected() {
+	i := v.list.SelectedIndex()
+	if i < 0 || i >= len(v.entries) {
+		v.display.SetImage(nil)
+		v.info.SetText(fmt.Sprintf("共 %d 张截图，保存在 %s", len(v.entries), v.scheduler.Dir()))
+		return
+	}
+	entry := v.entries[i]
+	img, err := LoadCapture(entry)
+	if err != nil {
+		v.display.SetImage(nil)
+		v.info.SetText(fmt.Sprintf("读取截图失败: %v", err))
+		return
+	}
+	v.display.SetImage(img)
+	b := img.Bounds()
+	v.info.SetText(fmt.Sprintf("%d/%d  %s  %dx%d  %s", i+1, len(v.entries),
+		entry.Time.Format("2006-01-02 15:04:05"), b.Dx(), b.Dy(), filepath.Base(entry.Path)))
+}
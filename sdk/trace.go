@@ -0,0 +1,450 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gonutz/wui/v2"
+
+	"trpc.group/trpc-go/trpc-agent-go/agent"
+	agentevent "trpc.group/trpc-go/trpc-agent-go/event"
+	"trpc.group/trpc-go/trpc-agent-go/model"
+)
+
+// TraceKind 追踪条目类型
+type TraceKind string
+
+const (
+	TraceInput        TraceKind = "input"         // 用户输入
+	TraceModelRequest TraceKind = "model.request" // 发往模型的请求
+	TraceDelta        TraceKind = "delta"         // 流式增量（相邻增量合并为一条）
+	TraceToolCall     TraceKind = "tool.call"     // 模型发起的工具调用
+	TraceToolResult   TraceKind = "tool.result"   // 工具返回结果
+	TraceCompletion   TraceKind = "completion"    // 模型完整回复
+	TraceError        TraceKind = "error"         // 错误
+)
+
+// defaultTraceTurns 默认保留的对话轮数
+const defaultTraceTurns = 50
+
+// traceNotifyDelay 合并更新通知的间隔，流式增量很密集，查看器不必每个增量都刷新
+const traceNotifyDelay = 150 * time.Millisecond
+
+// TraceEntry 一条追踪记录
+type TraceEntry struct {
+	Kind    TraceKind
+	Time    time.Time
+	Elapsed time.Duration // 相对本轮开始的耗时
+	Author  string
+	Summary string
+	Detail  string
+	Count   int // 合并的增量个数
+}
+
+// TraceTurn 一轮助手对话的追踪
+type TraceTurn struct {
+	ID        int
+	SessionID string
+	Remote    bool // 由 Agent 服务端（A2A / OpenAI 接口）发起，只记录模型请求与回复
+	Input     string
+	Start     time.Time
+	Duration  time.Duration
+	Err       error
+	Entries   []TraceEntry
+}
+
+// Tracer 记录 runner 事件序列
+//
+// 界面对话与服务端发起的运行可能同时进行，进行中的轮次按会话 ID 区分。
+type Tracer struct {
+	mu       sync.Mutex
+	turns    []*TraceTurn
+	active   map[string]*TraceTurn // 进行中的轮次，按会话 ID
+	nextID   int
+	maxTurns int
+	onChange []func()
+	pending  bool // 已安排通知
+}
+
+// NewTracer 创建追踪器
+func NewTracer(maxTurns int) *Tracer {
+	if maxTurns <= 0 {
+		maxTurns = defaultTraceTurns
+	}
+	return &Tracer{maxTurns: maxTurns, active: make(map[string]*TraceTurn)}
+}
+
+// OnChange 订阅追踪更新，多次更新会合并为一次通知
+func (t *Tracer) OnChange(handler func()) {
+	t.mu.Lock()
+	t.onChange = append(t.onChange, handler)
+	t.mu.Unlock()
+}
+
+// Turns 获取所有轮次快照
+func (t *Tracer) Turns() []TraceTurn {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	turns := make([]TraceTurn, len(t.turns))
+	for i, turn := range t.turns {
+		turns[i] = *turn
+		turns[i].Entries = append([]TraceEntry(nil), turn.Entries...)
+	}
+	return turns
+}
+
+// Clear 清空追踪
+func (t *Tracer) Clear() {
+	t.mu.Lock()
+	t.turns = nil
+	t.active = make(map[string]*TraceTurn)
+	t.mu.Unlock()
+	t.notify()
+}
+
+// notify 在 traceNotifyDelay 后通知订阅者，期间的更新合并为一次
+func (t *Tracer) notify() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending || len(t.onChange) == 0 {
+		return
+	}
+	t.pending = true
+	time.AfterFunc(traceNotifyDelay, func() {
+		t.mu.Lock()
+		t.pending = false
+		handlers := t.onChange
+		t.mu.Unlock()
+		for _, h := range handlers {
+			h()
+		}
+	})
+}
+
+// begin 开始会话 sessionID 的新一轮
+func (t *Tracer) begin(sessionID, input string) {
+	t.start(sessionID, input, false)
+	t.add(sessionID, TraceEntry{Kind: TraceInput, Author: "user", Summary: input})
+}
+
+func (t *Tracer) start(sessionID, input string, remote bool) *TraceTurn {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	turn := &TraceTurn{ID: t.nextID, SessionID: sessionID, Remote: remote, Input: input, Start: time.Now()}
+	t.turns = append(t.turns, turn)
+	if len(t.turns) > t.maxTurns {
+		t.turns = t.turns[len(t.turns)-t.maxTurns:]
+	}
+	t.active[sessionID] = turn
+	return turn
+}
+
+// end 结束会话 sessionID 当前的一轮
+func (t *Tracer) end(sessionID string, err error) {
+	t.mu.Lock()
+	if turn := t.active[sessionID]; turn != nil {
+		turn.Duration = time.Since(turn.Start)
+		turn.Err = err
+		delete(t.active, sessionID)
+	}
+	t.mu.Unlock()
+	t.notify()
+}
+
+// add 向会话 sessionID 当前的一轮添加条目，相邻的增量会合并
+func (t *Tracer) add(sessionID string, e TraceEntry) {
+	t.mu.Lock()
+	turn := t.active[sessionID]
+	if turn == nil {
+		t.mu.Unlock()
+		return
+	}
+	e.Time = time.Now()
+	e.Elapsed = e.Time.Sub(turn.Start)
+	if n := len(turn.Entries); e.Kind == TraceDelta && n > 0 && turn.Entries[n-1].Kind == TraceDelta {
+		last := &turn.Entries[n-1]
+		last.Detail += e.Detail
+		last.Count++
+		last.Summary = fmt.Sprintf("%d 个增量，%d 字符", last.Count, len([]rune(last.Detail)))
+		last.Elapsed = e.Elapsed
+	} else {
+		if e.Kind == TraceDelta {
+			e.Count = 1
+			e.Summary = fmt.Sprintf("1 个增量，%d 字符", len([]rune(e.Detail)))
+		}
+		turn.Entries = append(turn.Entries, e)
+	}
+	t.mu.Unlock()
+	t.notify()
+}
+
+// invocationSession 模型回调所属运行的会话 ID 与用户消息
+func invocationSession(ctx context.Context) (string, string) {
+	inv, ok := agent.InvocationFromContext(ctx)
+	if !ok || inv == nil || inv.Session == nil {
+		return "", ""
+	}
+	return inv.Session.ID, inv.Message.Content
+}
+
+// modelCallbacks 记录发往模型的请求；服务端发起的运行没有经过 begin，
+// 在第一次请求模型时开始一轮，模型完整回复后结束
+func (t *Tracer) modelCallbacks() *model.Callbacks {
+	return model.NewCallbacks().RegisterBeforeModel(
+		func(ctx context.Context, args *model.BeforeModelArgs) (*model.BeforeModelResult, error) {
+			sessionID, input := invocationSession(ctx)
+			if sessionID == "" {
+				return nil, nil
+			}
+			t.mu.Lock()
+			_, known := t.active[sessionID]
+			t.mu.Unlock()
+			if !known {
+				t.start(sessionID, input, true)
+			}
+
+			req := args.Request
+			tools := make([]string, 0, len(req.Tools))
+			for name := range req.Tools {
+				tools = append(tools, name)
+			}
+			var detail strings.Builder
+			for _, m := range req.Messages {
+				fmt.Fprintf(&detail, "[%s] %s\n", m.Role, m.Content)
+			}
+			t.add(sessionID, TraceEntry{
+				Kind:    TraceModelRequest,
+				Summary: fmt.Sprintf("%d 条消息，%d 个工具 %v", len(req.Messages), len(tools), tools),
+				Detail:  detail.String(),
+			})
+			return nil, nil
+		}).RegisterAfterModel(
+		func(ctx context.Context, args *model.AfterModelArgs) (*model.AfterModelResult, error) {
+			sessionID, _ := invocationSession(ctx)
+			t.mu.Lock()
+			turn := t.active[sessionID]
+			t.mu.Unlock()
+			if turn == nil || !turn.Remote {
+				return nil, nil // 界面对话的回复由 record 记录
+			}
+			if args.Error != nil {
+				t.add(sessionID, TraceEntry{Kind: TraceError, Summary: args.Error.Error()})
+				t.end(sessionID, args.Error)
+				return nil, nil
+			}
+			if args.Response == nil || args.Response.IsPartial || len(args.Response.Choices) == 0 {
+				return nil, nil
+			}
+			msg := args.Response.Choices[0].Message
+			for _, call := range msg.ToolCalls {
+				t.add(sessionID, TraceEntry{Kind: TraceToolCall, Summary: call.Function.Name, Detail: string(call.Function.Arguments)})
+			}
+			if len(msg.ToolCalls) == 0 {
+				t.add(sessionID, TraceEntry{Kind: TraceCompletion, Summary: args.Response.Object, Detail: msg.Content})
+				t.end(sessionID, nil)
+			}
+			return nil, nil
+		})
+}
+
+// record 记录会话 sessionID 中的一个 runner 事件
+func (t *Tracer) record(sessionID string, evt *agentevent.Event) {
+	if evt == nil || evt.Response == nil {
+		return
+	}
+	if evt.Error != nil {
+		t.add(sessionID, TraceEntry{Kind: TraceError, Author: evt.Author, Summary: evt.Error.Message, Detail: evt.Error.Type})
+		return
+	}
+	if len(evt.Response.Choices) == 0 {
+		return
+	}
+
+	choice := evt.Response.Choices[0]
+	switch {
+	case evt.Response.Object == model.ObjectTypeToolResponse:
+		for _, c := range evt.Response.Choices {
+			t.add(sessionID, TraceEntry{
+				Kind:    TraceToolResult,
+				Author:  evt.Author,
+				Summary: c.Message.ToolName,
+				Detail:  c.Message.Content,
+			})
+		}
+	case evt.Response.IsPartial:
+		if choice.Delta.Content != "" {
+			t.add(sessionID, TraceEntry{Kind: TraceDelta, Author: evt.Author, Detail: choice.Delta.Content})
+		}
+	case len(choice.Message.ToolCalls) > 0:
+		for _, call := range choice.Message.ToolCalls {
+			t.add(sessionID, TraceEntry{
+				Kind:    TraceToolCall,
+				Author:  evt.Author,
+				Summary: call.Function.Name,
+				Detail:  string(call.Function.Arguments),
+			})
+		}
+	case choice.Message.Content != "":
+		summary := evt.Response.Object
+		if u := evt.Response.Usage; u != nil {
+			summary = fmt.Sprintf("%s，tokens %d/%d", summary, u.PromptTokens, u.CompletionTokens)
+		}
+		t.add(sessionID, TraceEntry{Kind: TraceCompletion, Author: evt.Author, Summary: summary, Detail: choice.Message.Content})
+	}
+}
+
+// Tracer 获取 AI 服务的追踪器
+func (a *AIService) Tracer() *Tracer {
+	return a.tracer
+}
+
+// --- 追踪查看器 ---
+
+// TraceViewer 追踪查看组件：左侧为轮次列表，右侧为所选轮次的事件序列
+type TraceViewer struct {
+	panel    *wui.Panel
+	turnList *wui.StringList
+	detail   *wui.TextEdit
+	tracer   *Tracer
+	turns    []TraceTurn
+	items    []string // 当前列表项
+	text     string   // 当前详情文字
+}
+
+// AddTraceViewer 添加追踪查看组件
+func (t *TabContext) AddTraceViewer(tracer *Tracer, x, y, w, h int) *TraceViewer {
+	const padding = 8
+	const buttonHeight = 28
+	const listWidth = 220
+
+	panel := wui.NewPanel()
+	panel.SetBounds(x, y, w, h)
+
+	turnList := wui.NewStringList()
+	turnList.SetBounds(padding, padding, listWidth, h-buttonHeight-padding*3)
+	panel.Add(turnList)
+
+	detail := wui.NewTextEdit()
+	detail.SetBounds(padding*2+listWidth, padding, w-listWidth-padding*3, h-padding*2)
+	detail.SetReadOnly(true)
+	panel.Add(detail)
+
+	v := &TraceViewer{panel: panel, turnList: turnList, detail: detail, tracer: tracer}
+	t.SetAccessible(turnList, "对话轮次", "")
+	t.SetAccessible(detail, "轮次详情", "")
+
+	clearBtn := wui.NewButton()
+	clearBtn.SetText("清空")
+	clearBtn.SetBounds(padding, h-buttonHeight-padding, listWidth, buttonHeight)
+	clearBtn.SetOnClick(tracer.Clear)
+	panel.Add(clearBtn)
+
+	turnList.SetOnChange(func(int) { v.showSelected() })
+	// 订阅回调在计时器 goroutine 中执行，控件只能在界面线程上更新
+	tracer.OnChange(func() { t.app.invoke(v.Refresh) })
+
+	t.panel.Add(panel)
+	v.Refresh()
+	return v
+}
+
+// RegisterTraceTab 注册内置的助手追踪调试Tab
+func (app *App) RegisterTraceTab(name string, ai *AIService) {
+	app.RegisterTab(name, func(t *TabContext) {
+		t.AddTraceViewer(ai.Tracer(), 10, 10, app.width-20, app.height-app.contentY-20)
+	})
+}
+
+// Refresh 重新加载追踪数据
+func (v *TraceViewer) Refresh() {
+	v.turns = v.tracer.Turns()
+	items := make([]string, len(v.turns))
+	for i, turn := range v.turns {
+		status := "…"
+		if turn.Duration > 0 {
+			status = turn.Duration.Round(time.Millisecond).String()
+		}
+		if turn.Err != nil {
+			status = "错误"
+		}
+		source := ""
+		if turn.Remote {
+			source = "[服务端] "
+		}
+		items[i] = fmt.Sprintf("#%d %s [%s] %s%s", turn.ID, turn.Start.Format("15:04:05"), status, source, truncate(turn.Input, 20))
+	}
+
+	// 列表没有变化时不重设，避免打断选择与滚动
+	if !equalStrings(items, v.items) {
+		selected := v.turnList.SelectedIndex()
+		v.turnList.SetItems(items)
+		if selected < 0 || selected >= len(items) {
+			selected = len(items) - 1
+		}
+		v.turnList.SetSelectedIndex(selected)
+		v.items = items
+	}
+	v.showSelected()
+}
+
+// showSelected 显示所选轮次的事件序列，内容不变时不重设文字
+func (v *TraceViewer) showSelected() {
+	text := ""
+	if i := v.turnList.SelectedIndex(); i >= 0 && i < len(v.turns) {
+		text = FormatTraceTurn(v.turns[i])
+	}
+	if text != v.text {
+		v.detail.SetText(text)
+		v.text = text
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatTraceTurn 将一轮追踪格式化为文本
+func FormatTraceTurn(turn TraceTurn) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "第 %d 轮  %s\r\n", turn.ID, turn.Start.Format("2006-01-02 15:04:05"))
+	if turn.Remote {
+		fmt.Fprintf(&b, "来源: 服务端，会话 %s\r\n", turn.SessionID)
+	}
+	if turn.Duration > 0 {
+		fmt.Fprintf(&b, "总耗时: %s\r\n", turn.Duration.Round(time.Millisecond))
+	}
+	if turn.Err != nil {
+		fmt.Fprintf(&b, "错误: %v\r\n", turn.Err)
+	}
+	b.WriteString("\r\n")
+	for _, e := range turn.Entries {
+		fmt.Fprintf(&b, "+%-8s %-14s %s %s\r\n", e.Elapsed.Round(time.Millisecond), e.Kind, e.Author, e.Summary)
+		if e.Detail != "" {
+			for _, line := range strings.Split(strings.TrimRight(e.Detail, "\n"), "\n") {
+				b.WriteString("        │ " + line + "\r\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// truncate 截断过长文本
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
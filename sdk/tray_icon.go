@@ -0,0 +1,403 @@
+package sdk
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gonutz/wui/v2"
+	"golang.org/x/image/font/basicfont"
+
+	"github.com/package-register/gui/imaging"
+	"github.com/package-register/gui/tray"
+)
+
+// TrayState 托盘图标状态
+type TrayState string
+
+const (
+	TrayIdle   TrayState = "idle"   // 空闲
+	TrayBusy   TrayState = "busy"   // AI 正在生成回复，默认播放旋转动画
+	TrayError  TrayState = "error"  // 出错
+	TrayUnread TrayState = "unread" // 窗口隐藏期间收到了回复
+)
+
+const (
+	trayIconSize      = 32                     // 合成图标的边长
+	trayFrameInterval = 120 * time.Millisecond // 默认动画帧间隔
+	trayBusyFrames    = 8
+)
+
+// defaultTrayTooltips 各状态默认的提示后缀，{n} 替换为角标数字
+var defaultTrayTooltips = map[TrayState]string{
+	TrayBusy:   "正在生成回复…",
+	TrayError:  "出错了",
+	TrayUnread: "{n} 条未读回复",
+}
+
+// trayLook 状态的图标帧与提示
+type trayLook struct {
+	frames   []image.Image // 多于一帧时按 interval 循环播放
+	interval time.Duration
+	custom   bool // 由用户设置，基础图标变化时不重新生成
+}
+
+// trayIcons 托盘图标状态：按状态、角标与动画帧合成图标，提示随状态更新
+type trayIcons struct {
+	tray  *tray.Tray
+	theme *Theme
+
+	mu       sync.Mutex
+	tooltip  string // 基础提示
+	looks    map[TrayState]*trayLook
+	tooltips map[TrayState]string
+	state    TrayState
+	badge    int
+	frame    int
+	cache    map[int][]byte // 当前状态与角标下各帧编码后的图标
+	stop     chan struct{}  // 正在播放的动画，nil 表示未播放
+	replies  int            // 正在生成的回复数
+}
+
+func newTrayIcons(t *tray.Tray, theme *Theme, icon []byte, tooltip string) *trayIcons {
+	ti := &trayIcons{
+		tray:     t,
+		theme:    theme,
+		tooltip:  tooltip,
+		looks:    make(map[TrayState]*trayLook),
+		tooltips: make(map[TrayState]string),
+		state:    TrayIdle,
+	}
+	if err := ti.setBase(icon); err != nil {
+		log.Printf("Tray icon ignored: %v", err)
+	}
+	return ti
+}
+
+// replyStarted AI 开始生成回复
+func (app *App) replyStarted() {
+	if app.trayIcons != nil {
+		app.trayIcons.replyStarted()
+	}
+}
+
+// replyFinished AI 回复生成结束，err 非 nil 表示失败
+func (app *App) replyFinished(err error) {
+	if app.trayIcons != nil {
+		app.trayIcons.replyFinished(err, app.visible)
+	}
+}
+
+// setBase 设置基础图标并重新生成未自定义的状态图标，icon 为空时按主题色生成
+func (ti *trayIcons) setBase(icon []byte) error {
+	base := ti.defaultBase()
+	var err error
+	if len(icon) > 0 {
+		var img image.Image
+		if img, err = imaging.DecodeIcon(icon); err == nil {
+			base = imaging.Resize(img, trayIconSize, trayIconSize, imaging.FilterCatmullRom)
+		}
+	}
+
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	for state, look := range map[TrayState]*trayLook{
+		TrayIdle:   {frames: []image.Image{base}},
+		TrayBusy:   {frames: ti.busyFrames(base), interval: trayFrameInterval},
+		TrayError:  {frames: []image.Image{ti.errorIcon(base)}},
+		TrayUnread: {frames: []image.Image{base}},
+	} {
+		if old := ti.looks[state]; old == nil || !old.custom {
+			ti.looks[state] = look
+		}
+	}
+	ti.changed()
+	return err
+}
+
+// setFrames 自定义状态的图标帧
+func (ti *trayIcons) setFrames(state TrayState, icons [][]byte, interval time.Duration) error {
+	if len(icons) == 0 {
+		return fmt.Errorf("状态 %s 没有图标", state)
+	}
+	frames := make([]image.Image, len(icons))
+	for i, icon := range icons {
+		img, err := imaging.DecodeIcon(icon)
+		if err != nil {
+			return fmt.Errorf("状态 %s 的第 %d 帧: %w", state, i+1, err)
+		}
+		frames[i] = imaging.Resize(img, trayIconSize, trayIconSize, imaging.FilterCatmullRom)
+	}
+	if interval <= 0 {
+		interval = trayFrameInterval
+	}
+
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	ti.looks[state] = &trayLook{frames: frames, interval: interval, custom: true}
+	if state == ti.state {
+		ti.changed()
+	}
+	return nil
+}
+
+func (ti *trayIcons) setTooltip(tooltip string) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	ti.tooltip = tooltip
+	ti.applyTooltip()
+}
+
+func (ti *trayIcons) setStateTooltip(state TrayState, tooltip string) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	ti.tooltips[state] = tooltip
+	ti.applyTooltip()
+}
+
+func (ti *trayIcons) setState(state TrayState) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	if state == ti.state {
+		return
+	}
+	ti.state = state
+	ti.changed()
+}
+
+func (ti *trayIcons) currentState() TrayState {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	return ti.state
+}
+
+func (ti *trayIcons) setBadge(n int) {
+	if n < 0 {
+		n = 0
+	}
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	if n == ti.badge {
+		return
+	}
+	ti.badge = n
+	ti.changed()
+}
+
+func (ti *trayIcons) currentBadge() int {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	return ti.badge
+}
+
+// replyStarted 开始生成回复，切换到忙碌状态
+func (ti *trayIcons) replyStarted() {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	ti.replies++
+	if ti.state != TrayBusy {
+		ti.state = TrayBusy
+		ti.changed()
+	}
+}
+
+// replyFinished 回复生成结束：全部结束后按结果切换到出错、未读（窗口隐藏时，角标加一）或空闲状态
+func (ti *trayIcons) replyFinished(err error, visible bool) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	if ti.replies > 0 {
+		ti.replies--
+	}
+	if err == nil && !visible {
+		ti.badge++
+	}
+	if ti.replies > 0 {
+		ti.changed()
+		return
+	}
+	switch {
+	case err != nil:
+		ti.state = TrayError
+	case ti.badge > 0:
+		ti.state = TrayUnread
+	default:
+		ti.state = TrayIdle
+	}
+	ti.changed()
+}
+
+// seen 窗口显示后清除未读与出错状态
+func (ti *trayIcons) seen() {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	ti.badge = 0
+	if ti.state == TrayUnread || ti.state == TrayError {
+		ti.state = TrayIdle
+	}
+	ti.changed()
+}
+
+// close 停止动画
+func (ti *trayIcons) close() {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	ti.stopAnimation()
+}
+
+// changed 状态、角标或图标变化后重新合成，调用方持有锁
+func (ti *trayIcons) changed() {
+	ti.cache = nil
+	ti.frame = 0
+	ti.stopAnimation()
+	if look := ti.look(); len(look.frames) > 1 {
+		ti.stop = make(chan struct{})
+		go ti.animate(ti.stop, look.interval)
+	}
+	ti.apply()
+}
+
+func (ti *trayIcons) stopAnimation() {
+	if ti.stop != nil {
+		close(ti.stop)
+		ti.stop = nil
+	}
+}
+
+// animate 按帧间隔切换图标，直到 stop 被关闭
+func (ti *trayIcons) animate(stop chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ti.mu.Lock()
+			if ti.stop == stop {
+				ti.frame = (ti.frame + 1) % len(ti.look().frames)
+				ti.applyIcon()
+			}
+			ti.mu.Unlock()
+		}
+	}
+}
+
+// look 当前状态的图标，未设置的状态使用空闲图标，调用方持有锁
+func (ti *trayIcons) look() *trayLook {
+	if look, ok := ti.looks[ti.state]; ok {
+		return look
+	}
+	return ti.looks[TrayIdle]
+}
+
+// apply 更新托盘图标与提示，调用方持有锁
+func (ti *trayIcons) apply() {
+	ti.applyIcon()
+	ti.applyTooltip()
+}
+
+func (ti *trayIcons) applyIcon() {
+	if data, ok := ti.cache[ti.frame]; ok {
+		ti.tray.SetIcon(data)
+		return
+	}
+	data, err := ti.render(ti.look().frames[ti.frame])
+	if err != nil {
+		log.Printf("Render tray icon failed: %v", err)
+		return
+	}
+	if ti.cache == nil {
+		ti.cache = make(map[int][]byte)
+	}
+	ti.cache[ti.frame] = data
+	ti.tray.SetIcon(data)
+}
+
+// render 在图标帧上绘制角标并编码为 ICO；未读状态没有数字时画一个圆点
+func (ti *trayIcons) render(frame image.Image) ([]byte, error) {
+	dst := image.NewRGBA(image.Rect(0, 0, trayIconSize, trayIconSize))
+	draw.Draw(dst, dst.Rect, frame, frame.Bounds().Min, draw.Src)
+	badge := nrgba(ti.theme.Error)
+	if text := imaging.BadgeText(ti.badge); text != "" {
+		imaging.DrawBadge(dst, text, badge, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	} else if ti.state == TrayUnread {
+		imaging.FillCircle(dst, image.Pt(trayIconSize-6, 5), 4, badge)
+	}
+	var buf bytes.Buffer
+	if err := imaging.EncodeICO(&buf, dst); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// applyTooltip 提示为“基础提示 - 状态说明”
+func (ti *trayIcons) applyTooltip() {
+	text, ok := ti.tooltips[ti.state]
+	if !ok {
+		text = defaultTrayTooltips[ti.state]
+	}
+	text = strings.ReplaceAll(text, "{n}", strconv.Itoa(ti.badge))
+	switch {
+	case text == "":
+		text = ti.tooltip
+	case ti.tooltip != "":
+		text = ti.tooltip + " - " + text
+	}
+	ti.tray.SetTooltip(text)
+}
+
+// defaultBase 未设置图标时使用的主题色圆形图标
+func (ti *trayIcons) defaultBase() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, trayIconSize, trayIconSize))
+	imaging.FillCircle(img, image.Pt(trayIconSize/2, trayIconSize/2), trayIconSize/2-2, nrgba(ti.theme.Primary))
+	face := basicfont.Face7x13
+	size := imaging.TextSize("AI", face)
+	imaging.DrawText(img, image.Pt((trayIconSize-size.X)/2, (trayIconSize-size.Y)/2), "AI", face, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	return img
+}
+
+// busyFrames 忙碌动画：右下角的圆形底板上旋转的圆弧
+func (ti *trayIcons) busyFrames(base image.Image) []image.Image {
+	center := image.Pt(trayIconSize-8, trayIconSize-8)
+	frames := make([]image.Image, trayBusyFrames)
+	for i := range frames {
+		img := image.NewRGBA(image.Rect(0, 0, trayIconSize, trayIconSize))
+		draw.Draw(img, img.Rect, base, base.Bounds().Min, draw.Src)
+		imaging.FillCircle(img, center, 7, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+		start := 2 * math.Pi * float64(i) / trayBusyFrames
+		var arc []image.Point
+		for a := 0.0; a <= 1.5*math.Pi; a += math.Pi / 12 {
+			arc = append(arc, image.Pt(
+				center.X+int(math.Round(4*math.Cos(start+a))),
+				center.Y+int(math.Round(4*math.Sin(start+a)))))
+		}
+		imaging.StrokePolyline(img, arc, 2, nrgba(ti.theme.Primary))
+		frames[i] = img
+	}
+	return frames
+}
+
+// errorIcon 出错图标：右下角红底白色感叹号
+func (ti *trayIcons) errorIcon(base image.Image) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, trayIconSize, trayIconSize))
+	draw.Draw(img, img.Rect, base, base.Bounds().Min, draw.Src)
+	center := image.Pt(trayIconSize-8, trayIconSize-8)
+	imaging.FillCircle(img, center, 7, nrgba(ti.theme.Error))
+	face := basicfont.Face7x13
+	size := imaging.TextSize("!", face)
+	imaging.DrawText(img, center.Sub(size.Div(2)), "!", face, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	return img
+}
+
+// nrgba wui 颜色转为不透明的 color.NRGBA
+func nrgba(c wui.Color) color.NRGBA {
+	return color.NRGBA{R: c.R(), G: c.G(), B: c.B(), A: 255}
+}
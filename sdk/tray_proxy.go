@@ -1,28 +1,136 @@
 package sdk
 
-import "github.com/package-register/gui/tray"
+import (
+	"log"
+	"syscall"
+	"time"
+
+	"github.com/package-register/gui/tray"
+)
+
+// TrayMenuItem 托盘菜单项句柄，托盘启动前后都可使用：修改标题、勾选状态等立即生效，
+// 增删或移动菜单项后菜单自动重建
+type TrayMenuItem = tray.Item
+
+// TrayClick 托盘图标的点击方式，作为 event.TrayClicked 的数据
+type TrayClick = tray.Click
+
+const (
+	TrayClickLeft   = tray.ClickLeft
+	TrayClickDouble = tray.ClickDouble
+	TrayClickRight  = tray.ClickRight
+)
+
+var procGetDoubleClickTime = syscall.NewLazyDLL("user32.dll").NewProc("GetDoubleClickTime")
+
+// doubleClickTime 系统设置的双击间隔
+func doubleClickTime() time.Duration {
+	ms, _, _ := procGetDoubleClickTime.Call()
+	return time.Duration(ms) * time.Millisecond
+}
 
 // TrayProxy 托盘代理，暴露给用户的简洁API
 type TrayProxy struct {
-	tray *tray.Tray
+	tray  *tray.Tray
+	icons *trayIcons
 }
 
 // AddMenuItem 添加菜单项
-func (p *TrayProxy) AddMenuItem(title, tooltip string, handler func()) {
-	p.tray.AddMenuItem(title, tooltip, handler)
+func (p *TrayProxy) AddMenuItem(title, tooltip string, handler func()) *TrayMenuItem {
+	return p.tray.AddMenuItem(title, tooltip, handler)
+}
+
+// AddCheckItem 添加复选菜单项，点击时切换勾选状态后调用 handler
+func (p *TrayProxy) AddCheckItem(title, tooltip string, checked bool, handler func(checked bool)) *TrayMenuItem {
+	return p.tray.Menu().AddCheckbox(title, tooltip, checked, handler)
+}
+
+// AddRadioItem 添加单选菜单项，group 相同的菜单项同时只有一个被勾选
+func (p *TrayProxy) AddRadioItem(group, title, tooltip string, checked bool, handler func()) *TrayMenuItem {
+	return p.tray.Menu().AddRadio(group, title, tooltip, checked, handler)
+}
+
+// AddSubmenu 添加子菜单，通过返回值的 AddItem、AddCheckbox、AddRadio 等方法添加其中的菜单项
+func (p *TrayProxy) AddSubmenu(title, tooltip string) *TrayMenuItem {
+	return p.tray.Menu().AddSubmenu(title, tooltip)
 }
 
 // AddSeparator 添加分隔符
-func (p *TrayProxy) AddSeparator() {
-	p.tray.AddSeparator()
+func (p *TrayProxy) AddSeparator() *TrayMenuItem {
+	return p.tray.AddSeparator()
+}
+
+// Menu 根菜单
+func (p *TrayProxy) Menu() *TrayMenuItem {
+	return p.tray.Menu()
+}
+
+// Rebuild 清空菜单后由 build 按当前状态重新添加，如根据 Tab 列表生成菜单
+func (p *TrayProxy) Rebuild(build func(menu *TrayMenuItem)) {
+	p.tray.Rebuild(build)
+}
+
+// OnClick 左键单击图标时调用 handler，默认切换窗口显示；设为 nil 时左键单击显示菜单
+func (p *TrayProxy) OnClick(handler func()) {
+	p.tray.OnClick(handler)
 }
 
-// SetIcon 设置图标
+// OnDoubleClick 双击图标时调用 handler；设置后单击要在双击间隔后才触发
+func (p *TrayProxy) OnDoubleClick(handler func()) {
+	p.tray.OnDoubleClick(handler)
+}
+
+// OnRightClick 右键单击图标时调用 handler 代替显示菜单，设为 nil 时恢复显示菜单
+func (p *TrayProxy) OnRightClick(handler func()) {
+	p.tray.OnRightClick(handler)
+}
+
+// SetIcon 设置图标（ICO、PNG 等），即空闲状态的图标；其他状态未单独设置时由它叠加标记生成
 func (p *TrayProxy) SetIcon(icon []byte) {
-	p.tray.SetIcon(icon)
+	if err := p.icons.setBase(icon); err != nil {
+		log.Printf("Tray icon ignored: %v", err)
+	}
 }
 
-// SetTooltip 设置提示
+// SetTooltip 设置提示，非空闲状态下后面附加状态说明，如 "oAo Agent - 正在生成回复…"
 func (p *TrayProxy) SetTooltip(tooltip string) {
-	p.tray.SetTooltip(tooltip)
+	p.icons.setTooltip(tooltip)
+}
+
+// SetState 切换图标状态
+//
+// AI 回复生成期间自动切换为 TrayBusy，结束后按结果切换为 TrayError、TrayUnread（窗口隐藏时）
+// 或 TrayIdle；窗口显示后未读与出错状态恢复为空闲。
+func (p *TrayProxy) SetState(state TrayState) {
+	p.icons.setState(state)
+}
+
+// State 当前图标状态
+func (p *TrayProxy) State() TrayState {
+	return p.icons.currentState()
+}
+
+// SetStateIcon 设置状态的图标，也可用于自定义的状态名称
+func (p *TrayProxy) SetStateIcon(state TrayState, icon []byte) error {
+	return p.icons.setFrames(state, [][]byte{icon}, 0)
+}
+
+// SetStateAnimation 设置状态的动画帧，按 interval 循环播放；interval 不大于 0 时使用默认间隔
+func (p *TrayProxy) SetStateAnimation(state TrayState, frames [][]byte, interval time.Duration) error {
+	return p.icons.setFrames(state, frames, interval)
+}
+
+// SetStateTooltip 设置状态的提示说明，{n} 替换为角标数字；设为空字符串时只显示基础提示
+func (p *TrayProxy) SetStateTooltip(state TrayState, tooltip string) {
+	p.icons.setStateTooltip(state, tooltip)
+}
+
+// SetBadge 设置图标右上角的数字角标，0 表示不显示，超过 99 显示为 "99+"
+func (p *TrayProxy) SetBadge(n int) {
+	p.icons.setBadge(n)
+}
+
+// Badge 当前角标数字，窗口隐藏期间每收到一条回复自动加一，窗口显示后清零
+func (p *TrayProxy) Badge() int {
+	return p.icons.currentBadge()
 }
@@ -0,0 +1,120 @@
+package tray
+
+import (
+	"time"
+
+	"github.com/package-register/gui/event"
+)
+
+// Click 托盘图标的点击方式，作为 event.TrayClicked 的数据
+type Click string
+
+const (
+	ClickLeft   Click = "left"
+	ClickDouble Click = "double"
+	ClickRight  Click = "right"
+)
+
+// defaultDoubleClick 默认双击间隔
+const defaultDoubleClick = 500 * time.Millisecond
+
+// OnClick 左键单击图标时调用 handler；未设置单击与双击回调时左键单击显示菜单
+func (t *Tray) OnClick(handler func()) {
+	t.mu.Lock()
+	t.onClick = handler
+	t.mu.Unlock()
+	t.bindTaps()
+}
+
+// OnDoubleClick 双击图标时调用 handler
+//
+// 设置后单击要等双击间隔结束、确认不是双击后才触发，没有双击需求时不要设置。
+func (t *Tray) OnDoubleClick(handler func()) {
+	t.mu.Lock()
+	t.onDoubleClick = handler
+	t.mu.Unlock()
+	t.bindTaps()
+}
+
+// OnRightClick 右键单击图标时调用 handler 代替显示菜单；nil 表示显示菜单
+func (t *Tray) OnRightClick(handler func()) {
+	t.mu.Lock()
+	t.onRightClick = handler
+	t.mu.Unlock()
+	t.bindTaps()
+}
+
+// SetDoubleClickInterval 设置双击间隔，应与系统设置一致
+func (t *Tray) SetDoubleClickInterval(d time.Duration) {
+	if d <= 0 {
+		d = defaultDoubleClick
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.doubleClick = d
+}
+
+// bindTaps 按已设置的回调接管图标点击，没有回调的按键仍显示菜单
+func (t *Tray) bindTaps() {
+	t.mu.Lock()
+	left := t.onClick != nil || t.onDoubleClick != nil
+	right := t.onRightClick != nil
+	t.mu.Unlock()
+
+	if left {
+		t.adapter.OnTapped(t.leftTapped)
+	} else {
+		t.adapter.OnTapped(nil)
+	}
+	if right {
+		t.adapter.OnSecondaryTapped(t.rightTapped)
+	} else {
+		t.adapter.OnSecondaryTapped(nil)
+	}
+}
+
+// leftTapped 左键单击：设置了双击回调时等待第二次点击，间隔内再次点击即为双击
+func (t *Tray) leftTapped() {
+	t.mu.Lock()
+	click, double := t.onClick, t.onDoubleClick
+	if double == nil {
+		t.mu.Unlock()
+		t.clicked(ClickLeft, click)
+		return
+	}
+	if t.pendingClick != nil && t.pendingClick.Stop() {
+		t.pendingClick = nil
+		t.mu.Unlock()
+		t.clicked(ClickDouble, double)
+		return
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(t.doubleClick, func() {
+		t.mu.Lock()
+		if t.pendingClick != timer {
+			t.mu.Unlock()
+			return
+		}
+		t.pendingClick = nil
+		click := t.onClick
+		t.mu.Unlock()
+		t.clicked(ClickLeft, click)
+	})
+	t.pendingClick = timer
+	t.mu.Unlock()
+}
+
+func (t *Tray) rightTapped() {
+	t.mu.Lock()
+	handler := t.onRightClick
+	t.mu.Unlock()
+	t.clicked(ClickRight, handler)
+}
+
+// clicked 调用点击回调并发布 event.TrayClicked
+func (t *Tray) clicked(c Click, handler func()) {
+	if handler != nil {
+		handler()
+	}
+	t.emit(event.TrayClicked, c)
+}
@@ -54,21 +54,15 @@ func (f *FyneAdapter) SetTooltip(tooltip string) {
 	systray.SetTooltip(tooltip)
 }
 
-// AddMenuItem 添加菜单项
-func (f *FyneAdapter) AddMenuItem(title, tooltip string, handler func()) MenuItem {
-	item := &FyneMenuItem{
-		item:    systray.AddMenuItem(title, tooltip),
-		handler: handler,
-	}
-
-	// 启动goroutine监听点击事件
-	if handler != nil {
-		go func() {
-			for range item.item.ClickedCh {
-				handler()
-			}
-		}()
+// AddMenuItem 添加菜单项，checkable 为 true 时在 Linux 上也显示勾选框
+func (f *FyneAdapter) AddMenuItem(title, tooltip string, checkable, checked bool, handler func()) MenuItem {
+	var native *systray.MenuItem
+	if checkable {
+		native = systray.AddMenuItemCheckbox(title, tooltip, checked)
+	} else {
+		native = systray.AddMenuItem(title, tooltip)
 	}
+	item := newFyneMenuItem(native, handler)
 
 	f.mutex.Lock()
 	f.menuItems = append(f.menuItems, item)
@@ -82,6 +76,25 @@ func (f *FyneAdapter) AddSeparator() {
 	systray.AddSeparator()
 }
 
+// ResetMenu 移除全部菜单项
+func (f *FyneAdapter) ResetMenu() {
+	systray.ResetMenu()
+
+	f.mutex.Lock()
+	f.menuItems = f.menuItems[:0]
+	f.mutex.Unlock()
+}
+
+// OnTapped 设置左键单击图标的回调
+func (f *FyneAdapter) OnTapped(handler func()) {
+	systray.SetOnTapped(handler)
+}
+
+// OnSecondaryTapped 设置右键单击图标的回调
+func (f *FyneAdapter) OnSecondaryTapped(handler func()) {
+	systray.SetOnSecondaryTapped(handler)
+}
+
 // Quit 退出托盘
 func (f *FyneAdapter) Quit() {
 	f.mutex.Lock()
@@ -106,6 +119,44 @@ type FyneMenuItem struct {
 	handler func()
 }
 
+// newFyneMenuItem 包装原生菜单项，启动 goroutine 监听点击事件，菜单项移除后通道关闭、goroutine 退出
+func newFyneMenuItem(native *systray.MenuItem, handler func()) *FyneMenuItem {
+	item := &FyneMenuItem{item: native, handler: handler}
+	if handler != nil {
+		go func() {
+			for range native.ClickedCh {
+				handler()
+			}
+		}()
+	}
+	return item
+}
+
+// AddSubMenuItem 添加子菜单项
+func (f *FyneMenuItem) AddSubMenuItem(title, tooltip string, checkable, checked bool, handler func()) MenuItem {
+	if f.item == nil {
+		return nil
+	}
+	if checkable {
+		return newFyneMenuItem(f.item.AddSubMenuItemCheckbox(title, tooltip, checked), handler)
+	}
+	return newFyneMenuItem(f.item.AddSubMenuItem(title, tooltip), handler)
+}
+
+// Remove 移除菜单项及其子菜单项，点击通道随之关闭
+func (f *FyneMenuItem) Remove() {
+	if f.item != nil {
+		f.item.Remove()
+	}
+}
+
+// AddSeparator 在子菜单中添加分隔符
+func (f *FyneMenuItem) AddSeparator() {
+	if f.item != nil {
+		f.item.AddSeparator()
+	}
+}
+
 // SetTitle 设置菜单项标题
 func (f *FyneMenuItem) SetTitle(title string) {
 	if f.item != nil {
@@ -1,13 +1,24 @@
 package tray
 
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/package-register/gui/event"
+)
+
 // Adapter 托盘适配器接口（底层实现）
 type Adapter interface {
 	Initialize(onReady func(), onExit func()) error
 	SetIcon(iconBytes []byte)
 	SetTitle(title string)
 	SetTooltip(tooltip string)
-	AddMenuItem(title, tooltip string, handler func()) MenuItem
+	AddMenuItem(title, tooltip string, checkable, checked bool, handler func()) MenuItem
 	AddSeparator()
+	ResetMenu()
+	OnTapped(handler func())          // 左键单击图标，nil 表示显示菜单
+	OnSecondaryTapped(handler func()) // 右键单击图标，nil 表示显示菜单
 	Quit()
 	IsRunning() bool
 }
@@ -24,6 +35,9 @@ type MenuItem interface {
 	Enable()
 	IsEnabled() bool
 	OnClick(handler func())
+	Remove() // 移除菜单项及其子菜单项
+	AddSubMenuItem(title, tooltip string, checkable, checked bool, handler func()) MenuItem
+	AddSeparator()
 }
 
 // Tray 托盘控制器（暴露给用户的接口）
@@ -32,18 +46,37 @@ type Tray struct {
 	icon    []byte
 	tooltip string
 	running bool
-	pending []func() // 待执行的操作队列
+
+	mu       sync.Mutex
+	root     *Item          // 菜单树，托盘启动时据此创建原生菜单，结构变化后同步变化的部分
+	batching int            // Rebuild 中，结构变化只记录不刷新
+	changed  map[*Item]bool // 子菜单项有变化、待同步原生菜单的菜单
+
+	// 图标点击
+	events        *event.Bus
+	onClick       func()
+	onDoubleClick func()
+	onRightClick  func()
+	doubleClick   time.Duration // 双击间隔
+	pendingClick  *time.Timer   // 等待判断是否为双击的单击
 }
 
 // NewTray 创建托盘控制器
 func NewTray() *Tray {
-	return &Tray{
-		adapter: NewFyneAdapter(),
-	}
+	return NewTrayWithAdapter(NewFyneAdapter())
+}
+
+// NewTrayWithAdapter 使用指定适配器创建托盘控制器
+func NewTrayWithAdapter(adapter Adapter) *Tray {
+	t := &Tray{adapter: adapter, doubleClick: defaultDoubleClick}
+	t.root = &Item{tray: t, kind: kindSubmenu}
+	return t
 }
 
 // SetIcon 设置图标
 func (t *Tray) SetIcon(icon []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	t.icon = icon
 	if t.running {
 		t.adapter.SetIcon(icon)
@@ -52,60 +85,152 @@ func (t *Tray) SetIcon(icon []byte) {
 
 // SetTooltip 设置提示
 func (t *Tray) SetTooltip(tooltip string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	t.tooltip = tooltip
 	if t.running {
 		t.adapter.SetTooltip(tooltip)
 	}
 }
 
+// Menu 根菜单，托盘启动前后都可修改
+func (t *Tray) Menu() *Item {
+	return t.root
+}
+
 // AddMenuItem 添加菜单项
-func (t *Tray) AddMenuItem(title, tooltip string, handler func()) MenuItem {
-	if t.running {
-		return t.adapter.AddMenuItem(title, tooltip, handler)
-	}
-	// 未就绪，缓存操作
-	t.pending = append(t.pending, func() {
-		t.adapter.AddMenuItem(title, tooltip, handler)
-	})
-	return nil
+func (t *Tray) AddMenuItem(title, tooltip string, handler func()) *Item {
+	return t.root.AddItem(title, tooltip, handler)
 }
 
 // AddSeparator 添加分隔符
-func (t *Tray) AddSeparator() {
-	if t.running {
-		t.adapter.AddSeparator()
-		return
+func (t *Tray) AddSeparator() *Item {
+	return t.root.AddSeparator()
+}
+
+// Rebuild 按当前状态重建整个菜单
+func (t *Tray) Rebuild(build func(menu *Item)) {
+	t.root.Rebuild(build)
+}
+
+// SetEventBus 设置发布托盘事件的事件总线：启动后发布 event.TrayReady，退出时发布 event.TrayExit，
+// 点击图标发布 event.TrayClicked（数据为 Click），点击菜单项发布 event.TrayMenuClicked（数据为菜单路径）
+func (t *Tray) SetEventBus(bus *event.Bus) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = bus
+}
+
+// emit 发布事件，调用方不能持有锁，以便事件处理函数操作托盘
+func (t *Tray) emit(typ event.Type, data interface{}) {
+	t.mu.Lock()
+	bus := t.events
+	t.mu.Unlock()
+	if bus != nil {
+		bus.Emit(typ, data)
 	}
-	t.pending = append(t.pending, func() {
-		t.adapter.AddSeparator()
-	})
 }
 
 // Quit 退出
+//
+// 底层托盘在 Quit 中同步调用退出回调，因此不能持锁调用。
 func (t *Tray) Quit() {
-	if t.running {
+	t.mu.Lock()
+	running := t.running
+	t.running = false
+	if t.pendingClick != nil {
+		t.pendingClick.Stop()
+		t.pendingClick = nil
+	}
+	t.mu.Unlock()
+	if running {
 		t.adapter.Quit()
-		t.running = false
 	}
 }
 
 // Start 启动托盘
 func (t *Tray) Start() error {
 	return t.adapter.Initialize(func() {
-		t.running = true
-		// 设置图标和提示
-		if len(t.icon) > 0 {
-			t.adapter.SetIcon(t.icon)
-		}
-		if t.tooltip != "" {
-			t.adapter.SetTooltip(t.tooltip)
-		}
-		// 执行缓存的操作
-		for _, fn := range t.pending {
-			fn()
-		}
-		t.pending = nil
+		t.ready()
+		t.emit(event.TrayReady, nil)
 	}, func() {
+		t.mu.Lock()
 		t.running = false
+		t.root.detach()
+		t.mu.Unlock()
+		t.emit(event.TrayExit, nil)
 	})
 }
+
+// ready 托盘就绪后设置图标、提示并创建菜单
+func (t *Tray) ready() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.running = true
+	// 设置图标和提示
+	if len(t.icon) > 0 {
+		t.adapter.SetIcon(t.icon)
+	}
+	if t.tooltip != "" {
+		t.adapter.SetTooltip(t.tooltip)
+	}
+	// 创建启动前添加的菜单
+	t.changed = nil
+	t.root.materializeChildren(t.adapter)
+}
+
+// menuChanged menu 的子菜单项增删或移动后同步原生菜单
+func (t *Tray) menuChanged(menu *Item) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.changed == nil {
+		t.changed = make(map[*Item]bool)
+	}
+	t.changed[menu] = true
+	if t.batching == 0 {
+		t.refresh()
+	}
+}
+
+// batch 执行 fn 期间的结构变化合并为一次刷新
+func (t *Tray) batch(fn func()) {
+	t.mu.Lock()
+	t.batching++
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.batching--
+		if t.batching == 0 && len(t.changed) > 0 {
+			t.refresh()
+		}
+	}()
+	fn()
+}
+
+// refresh 只重建有变化的菜单中变化的部分，调用方持有锁
+func (t *Tray) refresh() {
+	changed := make([]*Item, 0, len(t.changed))
+	for menu := range t.changed {
+		changed = append(changed, menu)
+	}
+	t.changed = nil
+	if !t.running {
+		return
+	}
+	// 先处理上层菜单，其中重建的子菜单已是最新，再处理时不会重复重建
+	sort.Slice(changed, func(a, b int) bool { return changed[a].depth() < changed[b].depth() })
+	for _, menu := range changed {
+		if menu.materialized() {
+			menu.sync(t.adapter)
+		}
+	}
+}
+
+// reset 销毁并按菜单树重新创建整个原生菜单，调用方持有锁
+func (t *Tray) reset() {
+	t.adapter.ResetMenu()
+	t.root.detach()
+	t.root.materializeChildren(t.adapter)
+}
@@ -0,0 +1,409 @@
+package tray
+
+import (
+	"strings"
+
+	"github.com/package-register/gui/event"
+)
+
+// itemKind 菜单项类型
+type itemKind int
+
+const (
+	kindNormal itemKind = iota
+	kindCheckbox
+	kindRadio
+	kindSeparator
+	kindSubmenu
+)
+
+// Item 菜单项句柄
+//
+// 菜单由 Tray 按 Item 树统一维护：托盘启动前添加的菜单项在启动时创建，
+// 启动后增删、移动菜单项只重建所在菜单中变化的部分，句柄始终有效。
+type Item struct {
+	tray     *Tray
+	parent   *Item
+	children []*Item
+
+	kind     itemKind
+	group    string // 单选组
+	title    string
+	tooltip  string
+	icon     []byte
+	checked  bool
+	disabled bool
+	handler  func()
+
+	native MenuItem // 当前原生菜单项，托盘未启动时为 nil
+	built  []*Item  // 已创建原生菜单项的子菜单项，按原生菜单中的顺序
+}
+
+// AddItem 添加子菜单项；在根菜单上调用即添加顶层菜单项
+func (i *Item) AddItem(title, tooltip string, handler func()) *Item {
+	return i.add(&Item{kind: kindNormal, title: title, tooltip: tooltip, handler: handler})
+}
+
+// AddCheckbox 添加复选菜单项，点击时切换勾选状态后调用 handler
+func (i *Item) AddCheckbox(title, tooltip string, checked bool, handler func(checked bool)) *Item {
+	item := &Item{kind: kindCheckbox, title: title, tooltip: tooltip, checked: checked}
+	if handler != nil {
+		item.handler = func() { handler(item.Checked()) }
+	}
+	return i.add(item)
+}
+
+// AddRadio 添加单选菜单项，同一菜单中 group 相同的菜单项同时只有一个被勾选
+func (i *Item) AddRadio(group, title, tooltip string, checked bool, handler func()) *Item {
+	item := i.add(&Item{kind: kindRadio, group: group, title: title, tooltip: tooltip, handler: handler})
+	if checked {
+		item.SetChecked(true)
+	}
+	return item
+}
+
+// AddSubmenu 添加子菜单，通过返回值的 AddItem 等方法添加其中的菜单项
+func (i *Item) AddSubmenu(title, tooltip string) *Item {
+	return i.add(&Item{kind: kindSubmenu, title: title, tooltip: tooltip})
+}
+
+// AddSeparator 添加分隔线
+func (i *Item) AddSeparator() *Item {
+	return i.add(&Item{kind: kindSeparator})
+}
+
+func (i *Item) add(item *Item) *Item {
+	t := i.tray
+	t.mu.Lock()
+	item.tray, item.parent = t, i
+	i.children = append(i.children, item)
+	t.mu.Unlock()
+	t.menuChanged(i)
+	return item
+}
+
+// Items 子菜单项
+func (i *Item) Items() []*Item {
+	i.tray.mu.Lock()
+	defer i.tray.mu.Unlock()
+	return append([]*Item(nil), i.children...)
+}
+
+// Index 在所属菜单中的位置，已移除时为 -1
+func (i *Item) Index() int {
+	i.tray.mu.Lock()
+	defer i.tray.mu.Unlock()
+	return i.index()
+}
+
+func (i *Item) index() int {
+	if i.parent == nil {
+		return -1
+	}
+	for n, sibling := range i.parent.children {
+		if sibling == i {
+			return n
+		}
+	}
+	return -1
+}
+
+// Remove 从菜单中移除（含子菜单项）
+func (i *Item) Remove() {
+	t := i.tray
+	t.mu.Lock()
+	n := i.index()
+	if n < 0 {
+		t.mu.Unlock()
+		return
+	}
+	parent := i.parent
+	parent.children = append(parent.children[:n], parent.children[n+1:]...)
+	i.parent = nil
+	t.mu.Unlock()
+	t.menuChanged(parent)
+}
+
+// MoveTo 移动到所属菜单中的第 index 个位置，超出范围时移到最后
+func (i *Item) MoveTo(index int) {
+	t := i.tray
+	t.mu.Lock()
+	n := i.index()
+	if n < 0 {
+		t.mu.Unlock()
+		return
+	}
+	siblings := append(i.parent.children[:n], i.parent.children[n+1:]...)
+	if index < 0 {
+		index = 0
+	}
+	if index > len(siblings) {
+		index = len(siblings)
+	}
+	siblings = append(siblings[:index], append([]*Item{i}, siblings[index:]...)...)
+	i.parent.children = siblings
+	parent := i.parent
+	t.mu.Unlock()
+	t.menuChanged(parent)
+}
+
+// Clear 移除全部子菜单项
+func (i *Item) Clear() {
+	i.tray.mu.Lock()
+	for _, child := range i.children {
+		child.parent = nil
+	}
+	i.children = nil
+	i.tray.mu.Unlock()
+	i.tray.menuChanged(i)
+}
+
+// Rebuild 清空子菜单项后由 build 按当前状态重新添加，原生菜单只刷新一次
+func (i *Item) Rebuild(build func(menu *Item)) {
+	t := i.tray
+	t.batch(func() {
+		i.Clear()
+		build(i)
+	})
+}
+
+// Title 标题
+func (i *Item) Title() string {
+	i.tray.mu.Lock()
+	defer i.tray.mu.Unlock()
+	return i.title
+}
+
+// SetTitle 设置标题
+func (i *Item) SetTitle(title string) {
+	i.update(func() { i.title = title }, func(m MenuItem) { m.SetTitle(title) })
+}
+
+// SetTooltip 设置提示
+func (i *Item) SetTooltip(tooltip string) {
+	i.update(func() { i.tooltip = tooltip }, func(m MenuItem) { m.SetTooltip(tooltip) })
+}
+
+// SetIcon 设置菜单项图标
+func (i *Item) SetIcon(icon []byte) {
+	i.update(func() { i.icon = icon }, func(m MenuItem) { m.SetIcon(icon) })
+}
+
+// Checked 是否勾选
+func (i *Item) Checked() bool {
+	i.tray.mu.Lock()
+	defer i.tray.mu.Unlock()
+	return i.checked
+}
+
+// SetChecked 设置勾选状态，不调用 handler；勾选单选菜单项时取消同组其他菜单项的勾选
+func (i *Item) SetChecked(checked bool) {
+	t := i.tray
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	i.setChecked(checked)
+	if checked && i.kind == kindRadio && i.parent != nil {
+		for _, sibling := range i.parent.children {
+			if sibling != i && sibling.kind == kindRadio && sibling.group == i.group {
+				sibling.setChecked(false)
+			}
+		}
+	}
+}
+
+func (i *Item) setChecked(checked bool) {
+	i.checked = checked
+	if i.native == nil {
+		return
+	}
+	if checked {
+		i.native.Check()
+	} else {
+		i.native.Uncheck()
+	}
+}
+
+// Enabled 是否可用
+func (i *Item) Enabled() bool {
+	i.tray.mu.Lock()
+	defer i.tray.mu.Unlock()
+	return !i.disabled
+}
+
+// SetEnabled 设置是否可用，不可用的菜单项显示为灰色
+func (i *Item) SetEnabled(enabled bool) {
+	i.update(func() { i.disabled = !enabled }, func(m MenuItem) {
+		if enabled {
+			m.Enable()
+		} else {
+			m.Disable()
+		}
+	})
+}
+
+// OnClick 替换点击回调
+func (i *Item) OnClick(handler func()) {
+	i.tray.mu.Lock()
+	i.handler = handler
+	i.tray.mu.Unlock()
+}
+
+// update 修改属性，已创建原生菜单项时同步
+func (i *Item) update(set func(), apply func(m MenuItem)) {
+	i.tray.mu.Lock()
+	defer i.tray.mu.Unlock()
+	set()
+	if i.native != nil {
+		apply(i.native)
+	}
+}
+
+// click 原生菜单项被点击：复选与单选先切换勾选状态，再调用 handler，最后发布 event.TrayMenuClicked
+func (i *Item) click() {
+	t := i.tray
+	t.mu.Lock()
+	if i.disabled {
+		t.mu.Unlock()
+		return
+	}
+	handler := i.handler
+	path := i.path()
+	t.mu.Unlock()
+
+	switch i.kind {
+	case kindCheckbox:
+		i.SetChecked(!i.Checked())
+	case kindRadio:
+		i.SetChecked(true)
+	}
+	if handler != nil {
+		handler()
+	}
+	t.emit(event.TrayMenuClicked, path)
+}
+
+// path 菜单路径，如 "切换到/主页"，调用方持有锁
+func (i *Item) path() string {
+	var titles []string
+	for item := i; item.parent != nil; item = item.parent {
+		titles = append([]string{item.title}, titles...)
+	}
+	return strings.Join(titles, "/")
+}
+
+// materialize 在 parent 下创建本菜单项及其子菜单项的原生菜单项，调用方持有锁
+func (i *Item) materialize(a Adapter, parent MenuItem) {
+	if i.kind == kindSeparator {
+		if parent == nil {
+			a.AddSeparator()
+		} else {
+			parent.AddSeparator()
+		}
+		return
+	}
+	checkable := i.kind == kindCheckbox || i.kind == kindRadio
+	var handler func()
+	if i.kind != kindSubmenu {
+		handler = i.click
+	}
+	if parent == nil {
+		i.native = a.AddMenuItem(i.title, i.tooltip, checkable, i.checked, handler)
+	} else {
+		i.native = parent.AddSubMenuItem(i.title, i.tooltip, checkable, i.checked, handler)
+	}
+	if i.native == nil {
+		return
+	}
+	if i.disabled {
+		i.native.Disable()
+	}
+	if len(i.icon) > 0 {
+		i.native.SetIcon(i.icon)
+	}
+	i.materializeChildren(a)
+}
+
+// materializeChildren 创建全部子菜单项的原生菜单项，根菜单的创建在托盘顶层，调用方持有锁
+func (i *Item) materializeChildren(a Adapter) {
+	for _, child := range i.children {
+		child.materialize(a, i.native)
+	}
+	i.built = append([]*Item(nil), i.children...)
+}
+
+// sync 对比已创建的原生菜单项与当前子菜单项，从第一处不同开始重建，调用方持有锁
+func (i *Item) sync(a Adapter) {
+	k := 0
+	for k < len(i.built) && k < len(i.children) && i.built[k] == i.children[k] {
+		k++
+	}
+	i.rebuildFrom(a, k)
+}
+
+// rebuildFrom 移除第 k 项起的原生菜单项，再按当前子菜单项重新创建，调用方持有锁
+//
+// 原生菜单项按创建先后排列，新建的只能排在末尾，因此第一处变化之后的菜单项都要重建。
+// 分隔线无法单独移除：遇到时连同本菜单在上一级菜单中重建，在根菜单中则重建整个菜单。
+func (i *Item) rebuildFrom(a Adapter, k int) {
+	for _, old := range i.built[k:] {
+		if old.kind != kindSeparator {
+			continue
+		}
+		n := -1
+		if i.parent != nil {
+			n = indexOf(i.parent.built, i)
+		}
+		if n < 0 {
+			i.tray.reset()
+		} else {
+			i.parent.rebuildFrom(a, n)
+		}
+		return
+	}
+	for _, old := range i.built[k:] {
+		if old.native != nil {
+			old.native.Remove()
+		}
+		old.detach()
+	}
+	for _, child := range i.children[k:] {
+		child.materialize(a, i.native)
+	}
+	i.built = append(i.built[:k:k], i.children[k:]...)
+}
+
+func indexOf(items []*Item, item *Item) int {
+	for n, it := range items {
+		if it == item {
+			return n
+		}
+	}
+	return -1
+}
+
+// depth 在菜单树中的层级，根菜单为 0
+func (i *Item) depth() int {
+	d := 0
+	for item := i; item.parent != nil; item = item.parent {
+		d++
+	}
+	return d
+}
+
+// materialized 是否仍在菜单树中且已创建原生菜单，调用方持有锁
+func (i *Item) materialized() bool {
+	item := i
+	for item.parent != nil {
+		item = item.parent
+	}
+	return item == i.tray.root && (i == i.tray.root || i.native != nil)
+}
+
+// detach 原生菜单已销毁，清除引用，调用方持有锁
+func (i *Item) detach() {
+	i.native = nil
+	for _, child := range i.built {
+		child.detach()
+	}
+	i.built = nil
+}